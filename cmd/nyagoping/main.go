@@ -2,6 +2,8 @@ package main
 
 import (
 	"nyagoPing/internal/application/usecase"
+	"nyagoPing/internal/domain/model"
+	"nyagoPing/internal/domain/repository"
 	"nyagoPing/internal/domain/service"
 	"nyagoPing/internal/infrastructure/persistence"
 	"nyagoPing/internal/infrastructure/ping"
@@ -15,16 +17,44 @@ const (
 )
 
 func main() {
-	pingRepo := ping.NewProBingRepository()
+	pingRepo := ping.NewProtocolRepository(map[model.Protocol]repository.PingRepository{
+		model.ProtocolICMP:      ping.NewProBingRepository(),
+		model.ProtocolTCP:       ping.NewTCPRepository(),
+		model.ProtocolHTTP:      ping.NewHTTPRepository(),
+		model.ProtocolUDP:       ping.NewUDPRepository(),
+		model.ProtocolDNS:       ping.NewUDPRepository(),
+		model.ProtocolSimulated: ping.NewSimulatedRepository(),
+	})
+	traceRepo := ping.NewTraceProtocolRepository(map[model.Protocol]repository.TraceRepository{
+		model.ProtocolICMP:      ping.NewICMPTraceRepository(),
+		model.ProtocolSimulated: ping.NewSimulatedTraceRepository(),
+	})
 	asciiRepo := persistence.NewFileASCIIArtRepository()
+	scenarioRepo := persistence.NewFileScenarioRepository()
+	sessionRepo := persistence.NewFileSessionRepository()
+	sessionExporter := persistence.NewCSVSessionExporter()
+	thresholdRepo := persistence.NewFileThresholdRepository()
 	artGenerator := service.NewASCIIArtGenerator()
-	pingUseCase := usecase.NewPingUseCase(pingRepo, asciiRepo, artGenerator)
+	pingUseCase := usecase.NewPingUseCase(pingRepo, asciiRepo, scenarioRepo, sessionRepo, sessionExporter, thresholdRepo, artGenerator)
+	multiPingUseCase := usecase.NewMultiPingUseCase(pingUseCase)
+	monitorUseCase := usecase.NewMonitorUseCase(pingUseCase)
+	replayUseCase := usecase.NewReplayUseCase(sessionRepo, thresholdRepo)
+	traceUseCase := usecase.NewTraceUseCase(traceRepo, asciiRepo, scenarioRepo)
+	mtrUseCase := usecase.NewMTRUseCase(traceUseCase)
 	generateUseCase := usecase.NewGenerateASCIIArtUseCase(asciiRepo, artGenerator)
 	presenter := cli.NewPresenter()
+	multiPresenter := cli.NewMultiPresenter()
 	cliApp := cli.NewCLI(
 		pingUseCase,
+		multiPingUseCase,
+		monitorUseCase,
+		replayUseCase,
+		traceUseCase,
+		mtrUseCase,
 		generateUseCase,
 		presenter,
+		multiPresenter,
+		presenter,
 		appName,
 		appVersion,
 		appDescription,
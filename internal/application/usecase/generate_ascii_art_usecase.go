@@ -28,6 +28,19 @@ type GenerateInput struct {
 	OutputPath     string
 	Width          int
 	SaveSeparately bool
+	// Color は画素の色を文字ごとに残したアートを作ります。
+	Color bool
+	// Mode は画素を文字に変換する方式です。
+	Mode service.RenderMode
+	// Dither は明るさや色を段階に丸めるときのディザリング方式です。
+	Dither service.DitherMode
+	// Invert、Contrast、Brightness、Gamma、AutoLevels は文字にする前の明るさの補正です。意味は service.GenerateOptions と同じです。
+	// Contrast と Gamma は nil の場合は補正なしの1です。
+	Invert     bool
+	Contrast   *float64
+	Brightness float64
+	Gamma      *float64
+	AutoLevels bool
 }
 
 type GenerateOutput struct {
@@ -36,11 +49,23 @@ type GenerateOutput struct {
 }
 
 func (uc *GenerateASCIIArtUseCase) Execute(input *GenerateInput) (*GenerateOutput, error) {
+	opts := service.GenerateOptions{
+		Width:      input.Width,
+		Color:      input.Color,
+		Mode:       input.Mode,
+		Dither:     input.Dither,
+		Invert:     input.Invert,
+		Contrast:   valueOr(input.Contrast, 1),
+		Brightness: input.Brightness,
+		Gamma:      valueOr(input.Gamma, 1),
+		AutoLevels: input.AutoLevels,
+	}
+
 	var arts []*model.ASCIIArt
 	var filenames []string
 
 	if input.ImageDir != "" {
-		generatedArts, generatedFilenames, err := uc.artGenerator.GenerateFromImagesInDirectory(input.ImageDir, input.Width)
+		generatedArts, generatedFilenames, err := uc.artGenerator.GenerateFromImagesInDirectory(input.ImageDir, opts)
 		if err != nil {
 			return nil, fmt.Errorf("ディレクトリからのアスキーアート生成エラー: %w", err)
 		}
@@ -53,7 +78,7 @@ func (uc *GenerateASCIIArtUseCase) Execute(input *GenerateInput) (*GenerateOutpu
 			}
 		}
 	} else if input.ImagePath != "" {
-		art, err := uc.artGenerator.GenerateFromImage(input.ImagePath, input.Width)
+		art, err := uc.artGenerator.GenerateFromImage(input.ImagePath, opts)
 		if err != nil {
 			return nil, fmt.Errorf("画像からのアスキーアート生成エラー: %w", err)
 		}
@@ -72,3 +97,11 @@ func (uc *GenerateASCIIArtUseCase) Execute(input *GenerateInput) (*GenerateOutpu
 		Filenames: filenames,
 	}, nil
 }
+
+// valueOr は p が nil なら def を、そうでなければ *p を返します。
+func valueOr(p *float64, def float64) float64 {
+	if p == nil {
+		return def
+	}
+	return *p
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"nyagoPing/internal/domain/model"
+	"sync"
+	"time"
+)
+
+// MonitorUseCase は各ホストへのPingを ctx がキャンセルされるまで繰り返します。
+type MonitorUseCase struct {
+	pingUseCase *PingUseCase
+}
+
+func NewMonitorUseCase(pingUseCase *PingUseCase) *MonitorUseCase {
+	return &MonitorUseCase{
+		pingUseCase: pingUseCase,
+	}
+}
+
+// Execute はホストごとのゴルーチンで、アート1枚分(または Count 回)のPingを1巡として繰り返します。
+// 巡と巡の間は Interval だけ空けます。1巡が失敗した場合は onError に渡し、間隔を空けて再試行します。
+// onFinish には巡ごとの統計が渡されます。
+// onRecv・onEvent・onFinish・onError はホストの添字付きで複数のゴルーチンから呼ばれます。
+func (uc *MonitorUseCase) Execute(
+	ctx context.Context,
+	input *MultiPingInput,
+	onRecv func(int, *model.PingPacket),
+	onEvent func(int, *model.PingEvent),
+	onFinish func(int, *model.PingStatistics),
+	onError func(int, error),
+) error {
+	if len(input.Hosts) == 0 {
+		return fmt.Errorf("ホスト名を指定してください")
+	}
+	if input.Interval <= 0 {
+		return fmt.Errorf("送信間隔は0より大きい必要があります: %v", input.Interval)
+	}
+
+	recording, err := uc.pingUseCase.startRecording(&input.PingInput)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for i, host := range input.Hosts {
+		hostInput := input.PingInput
+		hostInput.Host = host
+		hostInput.lane = i
+		hostInput.RecordPath = ""
+		hostInput.CSVPath = ""
+
+		hostRecv := func(packet *model.PingPacket) {
+			onRecv(i, packet)
+		}
+		hostEvent := func(event *model.PingEvent) {
+			onEvent(i, event)
+		}
+		hostFinish := func(stats *model.PingStatistics) {
+			onFinish(i, stats)
+		}
+		if recording != nil {
+			hostRecv, hostEvent, hostFinish = recording.wrap(host, hostRecv, hostEvent, hostFinish)
+		}
+
+		wg.Add(1)
+		go func(i int, hostInput *PingInput) {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				if err := uc.pingUseCase.Execute(ctx, hostInput, hostRecv, hostEvent, hostFinish); err != nil && ctx.Err() == nil {
+					onError(i, fmt.Errorf("%s: %w", hostInput.Host, err))
+				}
+
+				timer := time.NewTimer(hostInput.Interval)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+				case <-timer.C:
+				}
+			}
+		}(i, &hostInput)
+	}
+	wg.Wait()
+
+	if recording != nil {
+		return recording.close()
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"nyagoPing/internal/domain/model"
+	"nyagoPing/internal/domain/service"
+)
+
+func TestMonitorUseCase_Execute(t *testing.T) {
+	pingUseCase := NewPingUseCase(&stubPingRepository{}, &stubASCIIArtRepository{}, nil, nil, nil, nil, service.NewASCIIArtGenerator())
+	uc := NewMonitorUseCase(pingUseCase)
+	input := &MultiPingInput{
+		PingInput: PingInput{
+			Interval:       time.Millisecond,
+			AutoCountByArt: true,
+		},
+		Hosts: []string{"a.tld", "unreachable.tld"},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	received := 0
+	failures := 0
+	rounds := 0
+	err := uc.Execute(ctx, input,
+		func(lane int, packet *model.PingPacket) {
+			mu.Lock()
+			defer mu.Unlock()
+			if lane != 0 {
+				t.Errorf("lane = %d, want 0", lane)
+			}
+			received++
+			// アート3行分を超えて受信すれば巡を繰り返している
+			if received >= 7 {
+				cancel()
+			}
+		},
+		func(int, *model.PingEvent) {},
+		func(lane int, stats *model.PingStatistics) {
+			mu.Lock()
+			defer mu.Unlock()
+			rounds++
+		},
+		func(lane int, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if lane != 1 {
+				t.Errorf("lane = %d, want 1: %v", lane, err)
+			}
+			failures++
+		},
+	)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if received < 7 {
+		t.Errorf("受信数 = %d, want 7以上", received)
+	}
+	if rounds == 0 {
+		t.Error("巡ごとの統計が通知されませんでした")
+	}
+	if failures == 0 {
+		t.Error("失敗したホストのエラーが通知されませんでした")
+	}
+}
+
+// drawingPingRepository は Ping のたびに筋書きの乱数を1つ引いて覚えておきます。
+type drawingPingRepository struct {
+	mu    sync.Mutex
+	draws map[string][]int64
+}
+
+func (r *drawingPingRepository) Ping(
+	ctx context.Context,
+	target *model.PingTarget,
+	config *model.PingConfig,
+	art *model.ASCIIArt,
+	onRecv func(*model.PingPacket),
+	onEvent func(*model.PingEvent),
+	onFinish func(*model.PingStatistics),
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.draws[target.Host()] = append(r.draws[target.Host()], config.Scenario().Rand().Int63())
+	onFinish(model.NewPingStatistics(target.Host(), 0, 0, 0, 0, 0, 0, 0))
+	return nil
+}
+
+func TestMonitorUseCase_Execute_SimulationContinuesAcrossRounds(t *testing.T) {
+	pingRepo := &drawingPingRepository{draws: make(map[string][]int64)}
+	uc := NewMonitorUseCase(NewPingUseCase(pingRepo, &stubASCIIArtRepository{}, nil, nil, nil, nil, service.NewASCIIArtGenerator()))
+	input := &MultiPingInput{
+		PingInput: PingInput{
+			Interval:       time.Millisecond,
+			AutoCountByArt: true,
+			Simulate:       true,
+			Seed:           42,
+		},
+		Hosts: []string{"a.tld"},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rounds := 0
+	err := uc.Execute(ctx, input, func(int, *model.PingPacket) {}, func(int, *model.PingEvent) {},
+		func(int, *model.PingStatistics) {
+			rounds++
+			if rounds == 3 {
+				cancel()
+			}
+		},
+		func(lane int, err error) { t.Errorf("lane %d: %v", lane, err) },
+	)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	// 巡ごとにシードから引き直さず、同じ乱数の続きを引く
+	want := model.DefaultSimulationScenario(42).Rand()
+	draws := pingRepo.draws["a.tld"]
+	if len(draws) < 3 {
+		t.Fatalf("巡の数 = %d, want 3以上", len(draws))
+	}
+	for i, got := range draws {
+		if w := want.Int63(); got != w {
+			t.Errorf("%d巡目の乱数 = %d, want %d", i+1, got, w)
+		}
+	}
+}
+
+func TestMonitorUseCase_Execute_InvalidInput(t *testing.T) {
+	uc := NewMonitorUseCase(nil)
+	if err := uc.Execute(context.Background(), &MultiPingInput{PingInput: PingInput{Interval: time.Second}}, nil, nil, nil, nil); err == nil {
+		t.Error("Execute() ホストなしでエラーが発生しませんでした")
+	}
+}
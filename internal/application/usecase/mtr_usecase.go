@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"nyagoPing/internal/domain/model"
+	"sync"
+	"time"
+)
+
+// MTRUseCase は経路上の全ホップへのプローブを繰り返し、ホップごとのロスとRTTを集計します。
+type MTRUseCase struct {
+	traceUseCase *TraceUseCase
+}
+
+func NewMTRUseCase(traceUseCase *TraceUseCase) *MTRUseCase {
+	return &MTRUseCase{
+		traceUseCase: traceUseCase,
+	}
+}
+
+// MTRInput の Count は巡の回数です。0なら ctx がキャンセルされるまで続けます。
+type MTRInput struct {
+	TraceInput
+	Interval time.Duration
+	Count    int
+}
+
+// Execute は1巡ごとに全ホップへ同時にプローブを送り、巡が終わるたびに集計を onUpdate に渡します。
+// 宛先が分かるまでは MaxHops までのすべてのホップを、分かった後は宛先までのホップを調べます。
+// 送信に失敗したプローブはそのホップの応答なしとして数え、巡を続けます。ただし最初の巡ですべてのプローブが失敗した場合は、
+// ソケットの権限など続けても直らない原因とみなしてエラーを返します。
+func (uc *MTRUseCase) Execute(
+	ctx context.Context,
+	input *MTRInput,
+	onStart func(*model.PingTarget, *model.TraceConfig),
+	onUpdate func(*model.RouteStatistics),
+) error {
+	if input.Interval <= 0 {
+		return fmt.Errorf("送信間隔は0より大きい必要があります: %v", input.Interval)
+	}
+	if input.Count < 0 {
+		return fmt.Errorf("count は0以上である必要があります: %d", input.Count)
+	}
+
+	target, config, art, err := uc.traceUseCase.prepare(ctx, &input.TraceInput)
+	if err != nil {
+		return err
+	}
+	onStart(target, config)
+
+	route := model.NewRouteStatistics()
+	names := &nameCache{names: make(map[string]string)}
+	repo := uc.traceUseCase.traceRepo
+
+	for round := 0; input.Count == 0 || round < input.Count; round++ {
+		if round > 0 {
+			timer := time.NewTimer(input.Interval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil
+			case <-timer.C:
+			}
+		}
+
+		limit := config.MaxHops()
+		if route.Destination() > 0 {
+			limit = route.Destination()
+		}
+
+		hops := make([]*model.TraceHop, limit)
+		errs := make([]error, limit)
+		var wg sync.WaitGroup
+		for ttl := 1; ttl <= limit; ttl++ {
+			wg.Add(1)
+			go func(ttl int) {
+				defer wg.Done()
+				reply, err := repo.ProbeHop(ctx, target, config, ttl)
+				if err != nil {
+					errs[ttl-1] = err
+					reply = &model.TraceReply{}
+				}
+				hops[ttl-1] = uc.traceUseCase.newHop(ctx, ttl, reply, art, names)
+			}(ttl)
+		}
+		wg.Wait()
+
+		if ctx.Err() != nil {
+			return nil
+		}
+		if round == 0 && allFailed(errs) {
+			return errs[0]
+		}
+		for _, hop := range hops {
+			route.Observe(hop)
+		}
+		onUpdate(route)
+	}
+	return nil
+}
+
+func allFailed(errs []error) bool {
+	for _, err := range errs {
+		if err == nil {
+			return false
+		}
+	}
+	return true
+}
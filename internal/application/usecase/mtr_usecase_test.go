@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"nyagoPing/internal/domain/model"
+)
+
+func TestMTRUseCase_Execute(t *testing.T) {
+	repo := &stubTraceRepository{reachAt: 3, silent: map[int]bool{2: true}}
+	uc := NewMTRUseCase(NewTraceUseCase(repo, &stubASCIIArtRepository{}, nil))
+	input := &MTRInput{
+		TraceInput: TraceInput{Host: "a.tld", MaxHops: 10, Timeout: time.Second},
+		Interval:   time.Millisecond,
+		Count:      3,
+	}
+
+	updates := 0
+	var last []model.HopStatistics
+	err := uc.Execute(context.Background(), input,
+		func(*model.PingTarget, *model.TraceConfig) {},
+		func(route *model.RouteStatistics) {
+			updates++
+			last = route.Hops()
+		},
+	)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if updates != 3 {
+		t.Errorf("更新回数 = %d, want 3", updates)
+	}
+	if len(last) != 3 {
+		t.Fatalf("ホップ数 = %d, want 3", len(last))
+	}
+	for i, hop := range last {
+		if hop.Sent != 3 {
+			t.Errorf("hops[%d].Sent = %d, want 3", i, hop.Sent)
+		}
+	}
+	if got := last[1].Loss(); got != 100 {
+		t.Errorf("応答のないホップのロス = %v, want 100", got)
+	}
+	if got := last[2].Loss(); got != 0 || last[2].Name != "name-192.0.2.1" || last[2].ArtLine != "line3" {
+		t.Errorf("宛先のホップ = %+v", last[2])
+	}
+}
+
+func TestMTRUseCase_Execute_Cancel(t *testing.T) {
+	repo := &stubTraceRepository{reachAt: 2}
+	uc := NewMTRUseCase(NewTraceUseCase(repo, &stubASCIIArtRepository{}, nil))
+	input := &MTRInput{
+		TraceInput: TraceInput{Host: "a.tld", MaxHops: 10, Timeout: time.Second},
+		Interval:   time.Millisecond,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := 0
+	err := uc.Execute(ctx, input,
+		func(*model.PingTarget, *model.TraceConfig) {},
+		func(*model.RouteStatistics) {
+			updates++
+			if updates == 5 {
+				cancel()
+			}
+		},
+	)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if updates != 5 {
+		t.Errorf("更新回数 = %d, want 5", updates)
+	}
+}
+
+func TestMTRUseCase_Execute_ProbeError(t *testing.T) {
+	repo := &stubTraceRepository{reachAt: 3, failing: map[int]bool{2: true}}
+	uc := NewMTRUseCase(NewTraceUseCase(repo, &stubASCIIArtRepository{}, nil))
+	input := &MTRInput{
+		TraceInput: TraceInput{Host: "a.tld", MaxHops: 10, Timeout: time.Second},
+		Interval:   time.Millisecond,
+		Count:      3,
+	}
+
+	var last []model.HopStatistics
+	err := uc.Execute(context.Background(), input,
+		func(*model.PingTarget, *model.TraceConfig) {},
+		func(route *model.RouteStatistics) { last = route.Hops() },
+	)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	// 送信に失敗したホップはロスとして数え、巡は最後まで続く
+	if len(last) != 3 || last[1].Sent != 3 || last[1].Loss() != 100 || last[2].Loss() != 0 {
+		t.Errorf("hops = %+v", last)
+	}
+}
+
+func TestMTRUseCase_Execute_AllProbesFail(t *testing.T) {
+	repo := &stubTraceRepository{err: errFailingHop}
+	uc := NewMTRUseCase(NewTraceUseCase(repo, &stubASCIIArtRepository{}, nil))
+	input := &MTRInput{
+		TraceInput: TraceInput{Host: "a.tld", MaxHops: 10, Timeout: time.Second},
+		Interval:   time.Millisecond,
+		Count:      3,
+	}
+	err := uc.Execute(context.Background(), input, func(*model.PingTarget, *model.TraceConfig) {}, func(*model.RouteStatistics) {})
+	if !errors.Is(err, errFailingHop) {
+		t.Errorf("Execute() error = %v, want %v", err, errFailingHop)
+	}
+}
+
+func TestMTRUseCase_Execute_InvalidInput(t *testing.T) {
+	uc := NewMTRUseCase(NewTraceUseCase(&stubTraceRepository{}, &stubASCIIArtRepository{}, nil))
+	for _, input := range []*MTRInput{
+		{TraceInput: TraceInput{Host: "a.tld", MaxHops: 10, Timeout: time.Second}},
+		{TraceInput: TraceInput{Host: "a.tld", MaxHops: 10, Timeout: time.Second}, Interval: time.Second, Count: -1},
+	} {
+		if err := uc.Execute(context.Background(), input, nil, nil); err == nil {
+			t.Errorf("Execute(%+v) でエラーが発生しませんでした", input)
+		}
+	}
+}
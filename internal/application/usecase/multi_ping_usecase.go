@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nyagoPing/internal/domain/model"
+	"sync"
+)
+
+type MultiPingUseCase struct {
+	pingUseCase *PingUseCase
+}
+
+func NewMultiPingUseCase(pingUseCase *PingUseCase) *MultiPingUseCase {
+	return &MultiPingUseCase{
+		pingUseCase: pingUseCase,
+	}
+}
+
+// MultiPingInput は PingInput の設定を全ホストで共有します。PingInput.Host は使われません。
+type MultiPingInput struct {
+	PingInput
+	Hosts []string
+}
+
+// Execute は各ホストを個別のゴルーチンで同時にPingします。
+// onRecv と onEvent はホストの添字付きで複数のゴルーチンから呼ばれるため、呼び出し側で排他制御してください。
+// onFinish には Hosts と同じ並びの統計が渡され、失敗したホストの要素は nil になります。
+func (uc *MultiPingUseCase) Execute(
+	ctx context.Context,
+	input *MultiPingInput,
+	onRecv func(int, *model.PingPacket),
+	onEvent func(int, *model.PingEvent),
+	onFinish func([]*model.PingStatistics),
+) error {
+	if len(input.Hosts) == 0 {
+		return fmt.Errorf("ホスト名を指定してください")
+	}
+
+	recording, err := uc.pingUseCase.startRecording(&input.PingInput)
+	if err != nil {
+		return err
+	}
+
+	stats := make([]*model.PingStatistics, len(input.Hosts))
+	errs := make([]error, len(input.Hosts)+1)
+
+	var wg sync.WaitGroup
+	for i, host := range input.Hosts {
+		hostInput := input.PingInput
+		hostInput.Host = host
+		hostInput.lane = i
+		hostInput.RecordPath = ""
+		hostInput.CSVPath = ""
+
+		hostRecv := func(packet *model.PingPacket) {
+			onRecv(i, packet)
+		}
+		hostEvent := func(event *model.PingEvent) {
+			onEvent(i, event)
+		}
+		hostFinish := func(s *model.PingStatistics) {
+			stats[i] = s
+		}
+		if recording != nil {
+			hostRecv, hostEvent, hostFinish = recording.wrap(host, hostRecv, hostEvent, hostFinish)
+		}
+
+		wg.Add(1)
+		go func(i int, hostInput *PingInput) {
+			defer wg.Done()
+			err := uc.pingUseCase.Execute(ctx, hostInput, hostRecv, hostEvent, hostFinish)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", hostInput.Host, err)
+			}
+		}(i, &hostInput)
+	}
+	wg.Wait()
+
+	if recording != nil {
+		errs[len(input.Hosts)] = recording.close()
+	}
+
+	onFinish(stats)
+
+	return errors.Join(errs...)
+}
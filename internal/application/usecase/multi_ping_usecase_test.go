@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"nyagoPing/internal/domain/model"
+	"nyagoPing/internal/domain/service"
+)
+
+type stubPingRepository struct{}
+
+func (r *stubPingRepository) Ping(
+	ctx context.Context,
+	target *model.PingTarget,
+	config *model.PingConfig,
+	art *model.ASCIIArt,
+	onRecv func(*model.PingPacket),
+	onEvent func(*model.PingEvent),
+	onFinish func(*model.PingStatistics),
+) error {
+	if target.Host() == "unreachable.tld" {
+		return fmt.Errorf("名前解決に失敗しました")
+	}
+	for seq := 0; seq < config.Count(); seq++ {
+		if target.Host() == "lossy.tld" && seq == 1 {
+			onEvent(model.NewPingEvent(model.PingEventTimeout, seq, art.GetLineBySeq(seq), nil))
+			continue
+		}
+		onRecv(model.NewPingPacket(seq, 32, 64, net.IPv4(127, 0, 0, 1), time.Millisecond, art.GetLineBySeq(seq)))
+	}
+	onFinish(model.NewPingStatistics(target.Host(), config.Count(), config.Count(), 0, time.Millisecond, time.Millisecond, time.Millisecond, 0))
+	return nil
+}
+
+type stubASCIIArtRepository struct{}
+
+func (r *stubASCIIArtRepository) Load(path string) (*model.ASCIIArt, error) {
+	return model.NewASCIIArt([]string{"line1", "line2", "line3"})
+}
+
+func (r *stubASCIIArtRepository) Save(path string, art *model.ASCIIArt) error {
+	return nil
+}
+
+func newStubMultiPingUseCase() *MultiPingUseCase {
+	pingUseCase := NewPingUseCase(&stubPingRepository{}, &stubASCIIArtRepository{}, nil, nil, nil, nil, service.NewASCIIArtGenerator())
+	return NewMultiPingUseCase(pingUseCase)
+}
+
+func TestMultiPingUseCase_Execute(t *testing.T) {
+	uc := newStubMultiPingUseCase()
+	input := &MultiPingInput{
+		PingInput: PingInput{
+			Interval:       time.Second,
+			AutoCountByArt: true,
+		},
+		Hosts: []string{"a.tld", "b.tld", "lossy.tld"},
+	}
+
+	var mu sync.Mutex
+	received := make(map[int][]string)
+	missing := make(map[int][]int)
+	var stats []*model.PingStatistics
+
+	err := uc.Execute(
+		context.Background(),
+		input,
+		func(lane int, packet *model.PingPacket) {
+			mu.Lock()
+			defer mu.Unlock()
+			received[lane] = append(received[lane], packet.ArtLine)
+		},
+		func(lane int, event *model.PingEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			missing[lane] = append(missing[lane], event.Seq)
+		},
+		func(s []*model.PingStatistics) {
+			stats = s
+		},
+	)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	for lane := range input.Hosts[:2] {
+		if len(received[lane]) != 3 || len(missing[lane]) != 0 {
+			t.Errorf("lane %d 受信数 = %d, イベント = %v, want 3, なし", lane, len(received[lane]), missing[lane])
+		}
+	}
+	if len(received[2]) != 2 || len(missing[2]) != 1 || missing[2][0] != 1 {
+		t.Errorf("lane 2 受信数 = %d, イベント = %v, want 2, [1]", len(received[2]), missing[2])
+	}
+	if len(stats) != len(input.Hosts) {
+		t.Fatalf("統計数 = %d, want %d", len(stats), len(input.Hosts))
+	}
+	for i, host := range input.Hosts {
+		if stats[i] == nil || stats[i].Addr != host {
+			t.Errorf("stats[%d] = %v, want Addr %s", i, stats[i], host)
+		}
+	}
+}
+
+func TestMultiPingUseCase_Execute_PartialFailure(t *testing.T) {
+	uc := newStubMultiPingUseCase()
+	input := &MultiPingInput{
+		PingInput: PingInput{
+			Interval:       time.Second,
+			AutoCountByArt: true,
+		},
+		Hosts: []string{"a.tld", "unreachable.tld"},
+	}
+
+	var stats []*model.PingStatistics
+	err := uc.Execute(context.Background(), input, func(int, *model.PingPacket) {}, func(int, *model.PingEvent) {}, func(s []*model.PingStatistics) {
+		stats = s
+	})
+	if err == nil {
+		t.Error("Execute() 失敗したホストのエラーが返されませんでした")
+	}
+	if stats[0] == nil {
+		t.Error("成功したホストの統計がありません")
+	}
+	if stats[1] != nil {
+		t.Errorf("失敗したホストの統計 = %v, want nil", stats[1])
+	}
+}
+
+// seedRecordingPingRepository はホストごとにシミュレーションの乱数シードを覚えておきます。
+type seedRecordingPingRepository struct {
+	mu    sync.Mutex
+	seeds map[string]int64
+}
+
+func (r *seedRecordingPingRepository) Ping(
+	ctx context.Context,
+	target *model.PingTarget,
+	config *model.PingConfig,
+	art *model.ASCIIArt,
+	onRecv func(*model.PingPacket),
+	onEvent func(*model.PingEvent),
+	onFinish func(*model.PingStatistics),
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seeds[target.Host()] = config.Scenario().Seed()
+	return nil
+}
+
+func TestMultiPingUseCase_Execute_SeedPerHost(t *testing.T) {
+	tests := []struct {
+		name string
+		seed int64
+		want map[string]int64
+	}{
+		{name: "ホストごとに混ぜる", seed: 42, want: map[string]int64{"a.tld": 42, "b.tld": model.LaneSeed(42, 1), "c.tld": model.LaneSeed(42, 2)}},
+		{name: "0は毎回変わるまま", seed: 0, want: map[string]int64{"a.tld": 0, "b.tld": 0, "c.tld": 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pingRepo := &seedRecordingPingRepository{seeds: make(map[string]int64)}
+			uc := NewMultiPingUseCase(NewPingUseCase(pingRepo, &stubASCIIArtRepository{}, nil, nil, nil, nil, service.NewASCIIArtGenerator()))
+			input := &MultiPingInput{
+				PingInput: PingInput{
+					Interval:       time.Second,
+					AutoCountByArt: true,
+					Simulate:       true,
+					Seed:           tt.seed,
+				},
+				Hosts: []string{"a.tld", "b.tld", "c.tld"},
+			}
+			if err := uc.Execute(context.Background(), input, func(int, *model.PingPacket) {}, func(int, *model.PingEvent) {}, func([]*model.PingStatistics) {}); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			for host, want := range tt.want {
+				if got := pingRepo.seeds[host]; got != want {
+					t.Errorf("%s のシード = %d, want %d", host, got, want)
+				}
+			}
+		})
+	}
+}
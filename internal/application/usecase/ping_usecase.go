@@ -1,27 +1,43 @@
 package usecase
 
 import (
+	"context"
+	"encoding/hex"
 	"fmt"
 	"nyagoPing/internal/domain/model"
 	"nyagoPing/internal/domain/repository"
 	"nyagoPing/internal/domain/service"
+	"strings"
+	"time"
 )
 
 type PingUseCase struct {
-	pingRepo    repository.PingRepository
-	asciiRepo   repository.ASCIIArtRepository
-	artGenerator *service.ASCIIArtGenerator
+	pingRepo        repository.PingRepository
+	asciiRepo       repository.ASCIIArtRepository
+	scenarioRepo    repository.ScenarioRepository
+	sessionRepo     repository.SessionRepository
+	sessionExporter repository.SessionExporter
+	thresholdRepo   repository.ThresholdRepository
+	artGenerator    *service.ASCIIArtGenerator
 }
 
 func NewPingUseCase(
 	pingRepo repository.PingRepository,
 	asciiRepo repository.ASCIIArtRepository,
+	scenarioRepo repository.ScenarioRepository,
+	sessionRepo repository.SessionRepository,
+	sessionExporter repository.SessionExporter,
+	thresholdRepo repository.ThresholdRepository,
 	artGenerator *service.ASCIIArtGenerator,
 ) *PingUseCase {
 	return &PingUseCase{
-		pingRepo:     pingRepo,
-		asciiRepo:    asciiRepo,
-		artGenerator: artGenerator,
+		pingRepo:        pingRepo,
+		asciiRepo:       asciiRepo,
+		scenarioRepo:    scenarioRepo,
+		sessionRepo:     sessionRepo,
+		sessionExporter: sessionExporter,
+		thresholdRepo:   thresholdRepo,
+		artGenerator:    artGenerator,
 	}
 }
 
@@ -29,13 +45,47 @@ type PingInput struct {
 	Host           string
 	Count          int
 	Privileged     bool
+	Interval       time.Duration
+	Timeout        time.Duration
+	Deadline       time.Duration
+	Size           int
+	TTL            int
+	Pattern        string
+	ForceIPv4      bool
+	ForceIPv6      bool
+	TCPPort        int
+	HTTPMethod     string
+	UDPPort        int
+	DNSQuery       string
+	DNSPort        int
+	Simulate       bool
+	ScenarioPath   string
+	Seed           int64
+	RecordPath     string
+	CSVPath        string
 	ASCIIArtPath   string
 	AutoCountByArt bool
+	ThresholdInput
+
+	// lane は複数ホストを同時に計測するときのホストの添字です。ホストごとに違う乱数でシミュレーションするため、0以外のシードと混ぜます。
+	lane int
+	// scenario は最初の Execute で読み込んだ筋書きです。監視で同じ入力を繰り返し計測するとき、乱数を巡をまたいで引き継ぐために使い回します。
+	scenario *model.SimulationScenario
+}
+
+// ThresholdInput はRTTの分類に使う閾値です。
+// 閾値ファイルの値を WarnRtt と BadRtt で上書きし、どちらも無ければ既定値を使います。
+type ThresholdInput struct {
+	WarnRtt        time.Duration
+	BadRtt         time.Duration
+	ThresholdsPath string
 }
 
 func (uc *PingUseCase) Execute(
+	ctx context.Context,
 	input *PingInput,
 	onRecv func(*model.PingPacket),
+	onEvent func(*model.PingEvent),
 	onFinish func(*model.PingStatistics),
 ) error {
 	target, err := model.NewPingTarget(input.Host)
@@ -53,10 +103,153 @@ func (uc *PingUseCase) Execute(
 		count = uc.artGenerator.CalculateOptimalCount(art)
 	}
 
-	config, err := model.NewPingConfig(count, input.Privileged)
+	config, err := model.NewPingConfig(count, input.Privileged, input.Interval, input.Timeout, input.Deadline)
+	if err != nil {
+		return fmt.Errorf("設定作成エラー: %w", err)
+	}
+	if err := config.SetSize(input.Size); err != nil {
+		return fmt.Errorf("設定作成エラー: %w", err)
+	}
+	if err := config.SetTTL(input.TTL); err != nil {
+		return fmt.Errorf("設定作成エラー: %w", err)
+	}
+	pattern, err := hex.DecodeString(input.Pattern)
+	if err != nil {
+		return fmt.Errorf("設定作成エラー: pattern は16進数で指定してください: %w", err)
+	}
+	if err := config.SetPattern(pattern); err != nil {
+		return fmt.Errorf("設定作成エラー: %w", err)
+	}
+	family, err := model.NewIPFamily(input.ForceIPv4, input.ForceIPv6)
+	if err != nil {
+		return fmt.Errorf("設定作成エラー: %w", err)
+	}
+	if err := config.SetFamily(family); err != nil {
+		return fmt.Errorf("設定作成エラー: %w", err)
+	}
+	if err := uc.applyProtocol(target, input, config); err != nil {
+		return fmt.Errorf("設定作成エラー: %w", err)
+	}
+	thresholds, err := loadThresholds(uc.thresholdRepo, &input.ThresholdInput)
 	if err != nil {
 		return fmt.Errorf("設定作成エラー: %w", err)
 	}
 
-	return uc.pingRepo.Ping(target, config, art, onRecv, onFinish)
+	recording, err := uc.startRecording(input)
+	if err != nil {
+		return err
+	}
+	if recording == nil {
+		return uc.pingRepo.Ping(ctx, target, config, art, classifyPackets(thresholds, onRecv), onEvent, onFinish)
+	}
+
+	// 記録されるパケットにも Level が入るよう、分類してから記録に渡す
+	onRecv, onEvent, onFinish = recording.wrap(target.Host(), onRecv, onEvent, onFinish)
+	if err := uc.pingRepo.Ping(ctx, target, config, art, classifyPackets(thresholds, onRecv), onEvent, onFinish); err != nil {
+		recording.close()
+		return err
+	}
+	return recording.close()
+}
+
+// applyProtocol は入力から計測プロトコルを1つ選んで config に設定します。
+func (uc *PingUseCase) applyProtocol(target *model.PingTarget, input *PingInput, config *model.PingConfig) error {
+	simulate := input.Simulate || input.ScenarioPath != ""
+
+	var selected []string
+	if input.TCPPort != 0 {
+		selected = append(selected, "--tcp")
+	}
+	if input.UDPPort != 0 {
+		selected = append(selected, "--udp")
+	}
+	if input.DNSQuery != "" {
+		selected = append(selected, "--dns")
+	}
+	if target.IsURL() {
+		selected = append(selected, "URL")
+	}
+	if simulate {
+		selected = append(selected, "--simulate")
+	}
+	if len(selected) > 1 {
+		return fmt.Errorf("%s は同時に指定できません", strings.Join(selected, ", "))
+	}
+
+	switch {
+	case simulate:
+		if input.scenario == nil {
+			scenario, err := loadScenario(uc.scenarioRepo, input.ScenarioPath, input.Seed)
+			if err != nil {
+				return err
+			}
+			if scenario.Seed() != 0 {
+				scenario.SetSeed(model.LaneSeed(scenario.Seed(), input.lane))
+			}
+			input.scenario = scenario
+		}
+		return config.SetSimulation(input.scenario)
+	case input.TCPPort != 0:
+		return config.SetProtocol(model.ProtocolTCP, input.TCPPort)
+	case input.UDPPort != 0:
+		return config.SetProtocol(model.ProtocolUDP, input.UDPPort)
+	case input.DNSQuery != "":
+		if err := config.SetQuery(input.DNSQuery); err != nil {
+			return err
+		}
+		return config.SetProtocol(model.ProtocolDNS, input.DNSPort)
+	case target.IsURL():
+		if err := config.SetMethod(input.HTTPMethod); err != nil {
+			return err
+		}
+		return config.SetProtocol(model.ProtocolHTTP, 0)
+	default:
+		return nil
+	}
+}
+
+// loadScenario は path の筋書きを読み込みます。path が空なら既定の筋書きを使い、seed が0以外ならシードを上書きします。
+func loadScenario(scenarioRepo repository.ScenarioRepository, path string, seed int64) (*model.SimulationScenario, error) {
+	if path == "" {
+		return model.DefaultSimulationScenario(seed), nil
+	}
+	scenario, err := scenarioRepo.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("シナリオ読み込みエラー: %w", err)
+	}
+	if seed != 0 {
+		scenario.SetSeed(seed)
+	}
+	return scenario, nil
+}
+
+func loadThresholds(thresholdRepo repository.ThresholdRepository, input *ThresholdInput) (*model.RttThresholds, error) {
+	thresholds := model.DefaultRttThresholds()
+	if input.ThresholdsPath != "" {
+		var err error
+		thresholds, err = thresholdRepo.Load(input.ThresholdsPath)
+		if err != nil {
+			return nil, fmt.Errorf("閾値ファイル読み込みエラー: %w", err)
+		}
+	}
+	if input.WarnRtt == 0 && input.BadRtt == 0 {
+		return thresholds, nil
+	}
+
+	warn, bad := thresholds.Warn(), thresholds.Bad()
+	if input.WarnRtt != 0 {
+		warn = input.WarnRtt
+	}
+	if input.BadRtt != 0 {
+		bad = input.BadRtt
+	}
+	return model.NewRttThresholds(warn, bad)
+}
+
+// classifyPackets は受信したパケットを閾値で分類してから onRecv に渡します。
+func classifyPackets(thresholds *model.RttThresholds, onRecv func(*model.PingPacket)) func(*model.PingPacket) {
+	return func(packet *model.PingPacket) {
+		packet.Level = thresholds.Classify(packet.Rtt)
+		onRecv(packet)
+	}
 }
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"nyagoPing/internal/domain/model"
+	"nyagoPing/internal/domain/repository"
+	"nyagoPing/internal/domain/service"
+)
+
+type stubThresholdRepository struct{}
+
+func (r *stubThresholdRepository) Load(path string) (*model.RttThresholds, error) {
+	return model.NewRttThresholds(500*time.Microsecond, 2*time.Millisecond)
+}
+
+func TestPingUseCase_Execute_ClassifiesPackets(t *testing.T) {
+	uc := NewPingUseCase(&stubPingRepository{}, &stubASCIIArtRepository{}, nil, nil, nil, &stubThresholdRepository{}, service.NewASCIIArtGenerator())
+
+	tests := []struct {
+		name      string
+		threshold ThresholdInput
+		want      model.LatencyLevel
+		wantErr   bool
+	}{
+		{name: "既定値", want: model.LatencyGood},
+		{name: "ファイル", threshold: ThresholdInput{ThresholdsPath: "thresholds.json"}, want: model.LatencyWarn},
+		{name: "フラグで上書き", threshold: ThresholdInput{ThresholdsPath: "thresholds.json", BadRtt: time.Millisecond}, want: model.LatencyBad},
+		{name: "フラグのみ", threshold: ThresholdInput{WarnRtt: 2 * time.Millisecond}, want: model.LatencyGood},
+		{name: "不正な組み合わせ", threshold: ThresholdInput{WarnRtt: 200 * time.Millisecond}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &PingInput{
+				Host:           "a.tld",
+				Interval:       time.Second,
+				AutoCountByArt: true,
+				ThresholdInput: tt.threshold,
+			}
+
+			var levels []model.LatencyLevel
+			err := uc.Execute(context.Background(), input,
+				func(packet *model.PingPacket) {
+					levels = append(levels, packet.Level)
+				},
+				func(*model.PingEvent) {},
+				func(*model.PingStatistics) {},
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(levels) != 3 {
+				t.Fatalf("受信数 = %d, want 3", len(levels))
+			}
+			for i, level := range levels {
+				if level != tt.want {
+					t.Errorf("levels[%d] = %v, want %v", i, level, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// stubSessionRepository は記録された時点のパケットの分類を覚えておきます。
+type stubSessionRepository struct {
+	levels []model.LatencyLevel
+}
+
+func (r *stubSessionRepository) Create(path string) (repository.SessionRecorder, error) {
+	return r, nil
+}
+
+func (r *stubSessionRepository) Load(path string) (*model.Session, error) {
+	return nil, fmt.Errorf("読み込みには対応していません")
+}
+
+func (r *stubSessionRepository) Record(record *model.SessionRecord) error {
+	if record.Packet != nil {
+		r.levels = append(r.levels, record.Packet.Level)
+	}
+	return nil
+}
+
+func (r *stubSessionRepository) Close() error {
+	return nil
+}
+
+func TestPingUseCase_Execute_RecordsClassifiedPackets(t *testing.T) {
+	sessionRepo := &stubSessionRepository{}
+	uc := NewPingUseCase(&stubPingRepository{}, &stubASCIIArtRepository{}, nil, sessionRepo, nil, &stubThresholdRepository{}, service.NewASCIIArtGenerator())
+
+	input := &PingInput{
+		Host:           "a.tld",
+		Interval:       time.Second,
+		AutoCountByArt: true,
+		RecordPath:     "session.jsonl",
+		ThresholdInput: ThresholdInput{ThresholdsPath: "thresholds.json"},
+	}
+	err := uc.Execute(context.Background(), input, func(*model.PingPacket) {}, func(*model.PingEvent) {}, func(*model.PingStatistics) {})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if len(sessionRepo.levels) != 3 {
+		t.Fatalf("記録数 = %d, want 3", len(sessionRepo.levels))
+	}
+	for i, level := range sessionRepo.levels {
+		if level != model.LatencyWarn {
+			t.Errorf("記録された levels[%d] = %v, want %v", i, level, model.LatencyWarn)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"fmt"
+	"nyagoPing/internal/domain/model"
+	"nyagoPing/internal/domain/repository"
+	"sync"
+	"time"
+)
+
+// sessionRecording は onRecv・onEvent・onFinish に渡された内容を1つ以上の記録先に書き出します。
+// 書き込みに失敗しても計測は止めず、最初のエラーを close で返します。
+type sessionRecording struct {
+	recorders []repository.SessionRecorder
+	mu        sync.Mutex
+	err       error
+}
+
+// startRecording は RecordPath と CSVPath のうち指定されたものへの記録を始めます。どちらも無ければ nil を返します。
+func (uc *PingUseCase) startRecording(input *PingInput) (*sessionRecording, error) {
+	r := &sessionRecording{}
+	if input.RecordPath != "" {
+		if err := r.add(uc.sessionRepo.Create, input.RecordPath); err != nil {
+			return nil, fmt.Errorf("セッション記録エラー: %w", err)
+		}
+	}
+	if input.CSVPath != "" {
+		if err := r.add(uc.sessionExporter.Create, input.CSVPath); err != nil {
+			r.close()
+			return nil, fmt.Errorf("CSV出力エラー: %w", err)
+		}
+	}
+	if len(r.recorders) == 0 {
+		return nil, nil
+	}
+	return r, nil
+}
+
+func (r *sessionRecording) add(create func(string) (repository.SessionRecorder, error), path string) error {
+	recorder, err := create(path)
+	if err != nil {
+		return err
+	}
+	r.recorders = append(r.recorders, recorder)
+	return nil
+}
+
+func (r *sessionRecording) wrap(
+	host string,
+	onRecv func(*model.PingPacket),
+	onEvent func(*model.PingEvent),
+	onFinish func(*model.PingStatistics),
+) (func(*model.PingPacket), func(*model.PingEvent), func(*model.PingStatistics)) {
+	return func(packet *model.PingPacket) {
+			r.record(&model.SessionRecord{Time: time.Now(), Host: host, Packet: packet})
+			onRecv(packet)
+		}, func(event *model.PingEvent) {
+			r.record(&model.SessionRecord{Time: time.Now(), Host: host, Event: event})
+			onEvent(event)
+		}, func(stats *model.PingStatistics) {
+			r.record(&model.SessionRecord{Time: time.Now(), Host: host, Statistics: stats})
+			onFinish(stats)
+		}
+}
+
+func (r *sessionRecording) record(record *model.SessionRecord) {
+	for _, recorder := range r.recorders {
+		if err := recorder.Record(record); err != nil {
+			r.mu.Lock()
+			if r.err == nil {
+				r.err = err
+			}
+			r.mu.Unlock()
+		}
+	}
+}
+
+func (r *sessionRecording) close() error {
+	var closeErr error
+	for _, recorder := range r.recorders {
+		if err := recorder.Close(); err != nil && closeErr == nil {
+			closeErr = err
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return fmt.Errorf("セッション記録エラー: %w", r.err)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("セッション記録エラー: %w", closeErr)
+	}
+	return nil
+}
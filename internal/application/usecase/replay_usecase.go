@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"nyagoPing/internal/domain/model"
+	"nyagoPing/internal/domain/repository"
+	"time"
+)
+
+type ReplayUseCase struct {
+	sessionRepo   repository.SessionRepository
+	thresholdRepo repository.ThresholdRepository
+}
+
+func NewReplayUseCase(sessionRepo repository.SessionRepository, thresholdRepo repository.ThresholdRepository) *ReplayUseCase {
+	return &ReplayUseCase{
+		sessionRepo:   sessionRepo,
+		thresholdRepo: thresholdRepo,
+	}
+}
+
+// ReplayInput の閾値は記録時ではなく再生時の指定で分類し直すために使います。
+type ReplayInput struct {
+	SessionPath string
+	// Speed は再生速度の倍率です。1で記録時と同じ速さ、0で待たずに再生します。
+	Speed float64
+	ThresholdInput
+}
+
+// Execute は記録されたセッションを記録時の間隔に Speed を掛けて再生します。
+// onStart には記録に現れたホストが渡され、onRecv・onEvent・onFinish にはそのホストの添字が渡されます。
+func (uc *ReplayUseCase) Execute(
+	ctx context.Context,
+	input *ReplayInput,
+	onStart func([]string),
+	onRecv func(int, *model.PingPacket),
+	onEvent func(int, *model.PingEvent),
+	onFinish func(int, *model.PingStatistics),
+) error {
+	if input.Speed < 0 {
+		return fmt.Errorf("再生速度は0以上である必要があります: %v", input.Speed)
+	}
+
+	thresholds, err := loadThresholds(uc.thresholdRepo, &input.ThresholdInput)
+	if err != nil {
+		return fmt.Errorf("設定作成エラー: %w", err)
+	}
+
+	session, err := uc.sessionRepo.Load(input.SessionPath)
+	if err != nil {
+		return fmt.Errorf("セッション読み込みエラー: %w", err)
+	}
+
+	hosts := session.Hosts()
+	lanes := make(map[string]int, len(hosts))
+	for i, host := range hosts {
+		lanes[host] = i
+	}
+	onStart(hosts)
+
+	records := session.Records()
+	for i, record := range records {
+		if i > 0 && input.Speed > 0 {
+			wait := time.Duration(float64(record.Time.Sub(records[i-1].Time)) / input.Speed)
+			if wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return nil
+				case <-timer.C:
+				}
+			}
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		lane := lanes[record.Host]
+		if record.Packet != nil {
+			record.Packet.Level = thresholds.Classify(record.Packet.Rtt)
+			record.Packet.Time = record.Time
+			onRecv(lane, record.Packet)
+		}
+		if record.Event != nil {
+			record.Event.Time = record.Time
+			onEvent(lane, record.Event)
+		}
+		if record.Statistics != nil {
+			record.Statistics.Time = record.Time
+			onFinish(lane, record.Statistics)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"nyagoPing/internal/domain/model"
+	"nyagoPing/internal/domain/repository"
+	"sync"
+	"time"
+)
+
+type TraceUseCase struct {
+	traceRepo    repository.TraceRepository
+	asciiRepo    repository.ASCIIArtRepository
+	scenarioRepo repository.ScenarioRepository
+}
+
+func NewTraceUseCase(
+	traceRepo repository.TraceRepository,
+	asciiRepo repository.ASCIIArtRepository,
+	scenarioRepo repository.ScenarioRepository,
+) *TraceUseCase {
+	return &TraceUseCase{
+		traceRepo:    traceRepo,
+		asciiRepo:    asciiRepo,
+		scenarioRepo: scenarioRepo,
+	}
+}
+
+type TraceInput struct {
+	Host         string
+	MaxHops      int
+	Timeout      time.Duration
+	ForceIPv4    bool
+	ForceIPv6    bool
+	Simulate     bool
+	ScenarioPath string
+	Seed         int64
+	ASCIIArtPath string
+}
+
+// Execute は TTL を1から1つずつ増やしてプローブを送り、ホップごとのアート行を onHop に渡します。
+// 宛先が応答するか、到達できないと通知されるか、MaxHops に達したところで終わります。
+// onStart には解決した宛先が渡されます。
+func (uc *TraceUseCase) Execute(
+	ctx context.Context,
+	input *TraceInput,
+	onStart func(*model.PingTarget, *model.TraceConfig),
+	onHop func(*model.TraceHop),
+) error {
+	target, config, art, err := uc.prepare(ctx, input)
+	if err != nil {
+		return err
+	}
+	onStart(target, config)
+
+	for ttl := 1; ttl <= config.MaxHops(); ttl++ {
+		reply, err := uc.traceRepo.ProbeHop(ctx, target, config, ttl)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		hop := uc.newHop(ctx, ttl, reply, art, nil)
+		onHop(hop)
+		if hop.IsLast() {
+			break
+		}
+	}
+	return nil
+}
+
+// prepare はアートを読み込み、設定を作って宛先を解決します。
+func (uc *TraceUseCase) prepare(ctx context.Context, input *TraceInput) (*model.PingTarget, *model.TraceConfig, *model.ASCIIArt, error) {
+	target, err := model.NewPingTarget(input.Host)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("ターゲット作成エラー: %w", err)
+	}
+	if target.IsURL() {
+		return nil, nil, nil, fmt.Errorf("trace にはURLを指定できません: %s", input.Host)
+	}
+
+	art, err := uc.asciiRepo.Load(input.ASCIIArtPath)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("アスキーアート読み込みエラー: %w", err)
+	}
+
+	config, err := uc.traceConfig(input)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("設定作成エラー: %w", err)
+	}
+
+	if err := uc.traceRepo.Resolve(ctx, target, config); err != nil {
+		return nil, nil, nil, err
+	}
+	return target, config, art, nil
+}
+
+// newHop は応答に逆引き名とアート行を添えたホップを作ります。names があれば逆引きの結果を使い回します。
+func (uc *TraceUseCase) newHop(ctx context.Context, ttl int, reply *model.TraceReply, art *model.ASCIIArt, names *nameCache) *model.TraceHop {
+	var name string
+	if reply.Addr != nil {
+		if names != nil {
+			name = names.lookup(ctx, uc.traceRepo, reply.Addr)
+		} else {
+			name = uc.traceRepo.LookupName(ctx, reply.Addr)
+		}
+	}
+	// ホップ番号は1から始まるため、1つ目のホップがアートの1行目になるようずらす
+	return model.NewTraceHop(ttl, reply, name, art.GetLineBySeq(ttl-1))
+}
+
+func (uc *TraceUseCase) traceConfig(input *TraceInput) (*model.TraceConfig, error) {
+	config, err := model.NewTraceConfig(input.MaxHops, input.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	family, err := model.NewIPFamily(input.ForceIPv4, input.ForceIPv6)
+	if err != nil {
+		return nil, err
+	}
+	if err := config.SetFamily(family); err != nil {
+		return nil, err
+	}
+
+	if input.Simulate || input.ScenarioPath != "" {
+		scenario, err := loadScenario(uc.scenarioRepo, input.ScenarioPath, input.Seed)
+		if err != nil {
+			return nil, err
+		}
+		if err := config.SetSimulation(scenario); err != nil {
+			return nil, err
+		}
+	}
+	return config, nil
+}
+
+// nameCache は同じアドレスの逆引きを巡ごとに繰り返さないよう結果を覚えておきます。
+type nameCache struct {
+	mu    sync.Mutex
+	names map[string]string
+}
+
+func (c *nameCache) lookup(ctx context.Context, repo repository.TraceRepository, addr net.IP) string {
+	key := addr.String()
+	c.mu.Lock()
+	name, ok := c.names[key]
+	c.mu.Unlock()
+	if ok {
+		return name
+	}
+
+	name = repo.LookupName(ctx, addr)
+	c.mu.Lock()
+	c.names[key] = name
+	c.mu.Unlock()
+	return name
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"nyagoPing/internal/domain/model"
+)
+
+// stubTraceRepository は宛先 reachAt ホップ目で到達し、silent のホップは応答しない経路を再現します。
+// err はすべてのホップで、failing のホップだけでは errFailingHop で送信に失敗します。
+type stubTraceRepository struct {
+	reachAt int
+	silent  map[int]bool
+	failing map[int]bool
+	err     error
+}
+
+var errFailingHop = errors.New("送信エラー")
+
+func (r *stubTraceRepository) Resolve(ctx context.Context, target *model.PingTarget, config *model.TraceConfig) error {
+	target.SetIP(net.IPv4(192, 0, 2, 1))
+	return nil
+}
+
+func (r *stubTraceRepository) ProbeHop(ctx context.Context, target *model.PingTarget, config *model.TraceConfig, ttl int) (*model.TraceReply, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.failing[ttl] {
+		return nil, errFailingHop
+	}
+	if r.silent[ttl] {
+		return &model.TraceReply{}, nil
+	}
+	if ttl >= r.reachAt {
+		return &model.TraceReply{Addr: target.IP(), Rtt: time.Duration(ttl) * time.Millisecond, Reached: true}, nil
+	}
+	return &model.TraceReply{Addr: net.IPv4(198, 51, 100, byte(ttl)), Rtt: time.Duration(ttl) * time.Millisecond}, nil
+}
+
+func (r *stubTraceRepository) LookupName(ctx context.Context, addr net.IP) string {
+	return "name-" + addr.String()
+}
+
+func TestTraceUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name      string
+		repo      *stubTraceRepository
+		maxHops   int
+		wantHops  int
+		wantLines []string
+		wantErr   bool
+	}{
+		{
+			name:      "宛先に到達",
+			repo:      &stubTraceRepository{reachAt: 4, silent: map[int]bool{2: true}},
+			maxHops:   30,
+			wantHops:  4,
+			wantLines: []string{"line1", "line2", "line3", "line1"},
+		},
+		{
+			name:      "最大ホップ数で打ち切り",
+			repo:      &stubTraceRepository{reachAt: 10},
+			maxHops:   2,
+			wantHops:  2,
+			wantLines: []string{"line1", "line2"},
+		},
+		{name: "プローブのエラー", repo: &stubTraceRepository{err: errors.New("boom")}, maxHops: 30, wantErr: true},
+		{name: "不正な最大ホップ数", repo: &stubTraceRepository{reachAt: 1}, maxHops: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewTraceUseCase(tt.repo, &stubASCIIArtRepository{}, nil)
+			input := &TraceInput{Host: "a.tld", MaxHops: tt.maxHops, Timeout: time.Second}
+
+			var started bool
+			var hops []*model.TraceHop
+			err := uc.Execute(context.Background(), input,
+				func(target *model.PingTarget, config *model.TraceConfig) {
+					started = target.IP() != nil
+				},
+				func(hop *model.TraceHop) {
+					hops = append(hops, hop)
+				},
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !started {
+				t.Error("onStart に解決済みの宛先が渡されませんでした")
+			}
+			if len(hops) != tt.wantHops {
+				t.Fatalf("ホップ数 = %d, want %d", len(hops), tt.wantHops)
+			}
+			for i, hop := range hops {
+				if hop.Hop != i+1 {
+					t.Errorf("hops[%d].Hop = %d, want %d", i, hop.Hop, i+1)
+				}
+				if hop.ArtLine != tt.wantLines[i] {
+					t.Errorf("hops[%d].ArtLine = %q, want %q", i, hop.ArtLine, tt.wantLines[i])
+				}
+				if tt.repo.silent[hop.Hop] {
+					if !hop.IsTimeout() || hop.Name != "" {
+						t.Errorf("hops[%d] 応答のないホップ = %+v", i, hop)
+					}
+				} else if hop.Name != "name-"+hop.Addr.String() {
+					t.Errorf("hops[%d].Name = %q", i, hop.Name)
+				}
+			}
+			if last := hops[len(hops)-1]; last.Reached != (tt.wantHops < tt.maxHops) {
+				t.Errorf("最後のホップの Reached = %v", last.Reached)
+			}
+		})
+	}
+}
@@ -1,9 +1,21 @@
 package model
 
-import "fmt"
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// RGB はアートの1文字分の色です。
+type RGB struct {
+	R, G, B uint8
+}
 
 type ASCIIArt struct {
 	lines []string
+	// colors は行ごと・文字ごとの色です。色のないアートでは nil です
+	colors [][]RGB
+	// backgrounds は行ごと・文字ごとの背景色です。背景色のないアートでは nil です
+	backgrounds [][]RGB
 }
 
 func NewASCIIArt(lines []string) (*ASCIIArt, error) {
@@ -15,6 +27,44 @@ func NewASCIIArt(lines []string) (*ASCIIArt, error) {
 	}, nil
 }
 
+// NewColoredASCIIArt は文字ごとの色を持つアートを作ります。colors[i] の長さは lines[i] の文字数と一致する必要があります。
+func NewColoredASCIIArt(lines []string, colors [][]RGB) (*ASCIIArt, error) {
+	art, err := NewASCIIArt(lines)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateCellColors(lines, colors); err != nil {
+		return nil, err
+	}
+	art.colors = colors
+	return art, nil
+}
+
+// NewColoredASCIIArtWithBackground は文字ごとの色と背景色を持つアートを作ります。上下の半ブロックで2画素を1文字に描く場合などに使います。
+func NewColoredASCIIArtWithBackground(lines []string, colors, backgrounds [][]RGB) (*ASCIIArt, error) {
+	art, err := NewColoredASCIIArt(lines, colors)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateCellColors(lines, backgrounds); err != nil {
+		return nil, fmt.Errorf("背景色: %w", err)
+	}
+	art.backgrounds = backgrounds
+	return art, nil
+}
+
+func validateCellColors(lines []string, colors [][]RGB) error {
+	if len(colors) != len(lines) {
+		return fmt.Errorf("色の行数が一致しません: %d行に対して%d行", len(lines), len(colors))
+	}
+	for i, line := range lines {
+		if n := utf8.RuneCountInString(line); len(colors[i]) != n {
+			return fmt.Errorf("%d行目の色の数が一致しません: %d文字に対して%d色", i+1, n, len(colors[i]))
+		}
+	}
+	return nil
+}
+
 func (aa *ASCIIArt) Lines() []string {
 	return aa.lines
 }
@@ -36,3 +86,45 @@ func (aa *ASCIIArt) GetLineBySeq(seq int) string {
 	}
 	return aa.lines[seq%len(aa.lines)]
 }
+
+// HasColor はアートが文字ごとの色を持つかどうかを返します。
+func (aa *ASCIIArt) HasColor() bool {
+	return aa.colors != nil
+}
+
+// GetColors は index 行目の文字ごとの色を返します。色のないアートでは nil を返します。
+func (aa *ASCIIArt) GetColors(index int) []RGB {
+	if aa.colors == nil || index < 0 || index >= len(aa.colors) {
+		return nil
+	}
+	return aa.colors[index]
+}
+
+// GetColorsBySeq は GetLineBySeq と同じ行の色を返します。
+func (aa *ASCIIArt) GetColorsBySeq(seq int) []RGB {
+	if len(aa.lines) == 0 {
+		return nil
+	}
+	return aa.GetColors(seq % len(aa.lines))
+}
+
+// HasBackground はアートが文字ごとの背景色を持つかどうかを返します。
+func (aa *ASCIIArt) HasBackground() bool {
+	return aa.backgrounds != nil
+}
+
+// GetBackgrounds は index 行目の文字ごとの背景色を返します。背景色のないアートでは nil を返します。
+func (aa *ASCIIArt) GetBackgrounds(index int) []RGB {
+	if aa.backgrounds == nil || index < 0 || index >= len(aa.backgrounds) {
+		return nil
+	}
+	return aa.backgrounds[index]
+}
+
+// GetBackgroundsBySeq は GetLineBySeq と同じ行の背景色を返します。
+func (aa *ASCIIArt) GetBackgroundsBySeq(seq int) []RGB {
+	if len(aa.lines) == 0 {
+		return nil
+	}
+	return aa.GetBackgrounds(seq % len(aa.lines))
+}
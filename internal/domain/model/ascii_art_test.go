@@ -103,3 +103,64 @@ func TestASCIIArt_LineCount(t *testing.T) {
 		})
 	}
 }
+
+func TestNewColoredASCIIArt(t *testing.T) {
+	red, blue := RGB{R: 255}, RGB{B: 255}
+	tests := []struct {
+		name    string
+		lines   []string
+		colors  [][]RGB
+		wantErr bool
+	}{
+		{"有効", []string{"ab", "ねこ"}, [][]RGB{{red, blue}, {blue, red}}, false},
+		{"行数の不一致", []string{"ab", "cd"}, [][]RGB{{red, blue}}, true},
+		{"文字数の不一致", []string{"ab"}, [][]RGB{{red}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			art, err := NewColoredASCIIArt(tt.lines, tt.colors)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewColoredASCIIArt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !art.HasColor() {
+				t.Error("HasColor() = false")
+			}
+			if got := art.GetColorsBySeq(3); len(got) != 2 || got[0] != blue {
+				t.Errorf("GetColorsBySeq(3) = %v", got)
+			}
+		})
+	}
+}
+
+func TestASCIIArt_GetColors_Plain(t *testing.T) {
+	art, _ := NewASCIIArt([]string{"line1"})
+	if art.HasColor() || art.GetColors(0) != nil || art.GetColorsBySeq(0) != nil {
+		t.Error("色のないアートが色を返しました")
+	}
+}
+
+func TestNewColoredASCIIArtWithBackground(t *testing.T) {
+	red, blue := RGB{R: 255}, RGB{B: 255}
+	art, err := NewColoredASCIIArtWithBackground([]string{"▀▀"}, [][]RGB{{red, blue}}, [][]RGB{{blue, red}})
+	if err != nil {
+		t.Fatalf("NewColoredASCIIArtWithBackground() error = %v", err)
+	}
+	if !art.HasBackground() {
+		t.Error("HasBackground() = false")
+	}
+	if got := art.GetBackgroundsBySeq(1); len(got) != 2 || got[0] != blue {
+		t.Errorf("GetBackgroundsBySeq(1) = %v", got)
+	}
+
+	if _, err := NewColoredASCIIArtWithBackground([]string{"▀▀"}, [][]RGB{{red, blue}}, [][]RGB{{blue}}); err == nil {
+		t.Error("背景色の数が一致しないのにエラーになりませんでした")
+	}
+
+	plain, _ := NewColoredASCIIArt([]string{"ab"}, [][]RGB{{red, blue}})
+	if plain.HasBackground() || plain.GetBackgrounds(0) != nil {
+		t.Error("背景色のないアートが背景色を返しました")
+	}
+}
@@ -0,0 +1,111 @@
+package model
+
+import (
+	"math"
+	"net"
+	"time"
+)
+
+// HopStatistics は経路上の1ホップへのプローブを繰り返した結果の集計です。
+// 平均と標準偏差は RTT を保持せずに逐次計算します。
+type HopStatistics struct {
+	Hop     int
+	Addr    net.IP
+	Name    string
+	ArtLine string
+	Sent    int
+	Recv    int
+	Last    time.Duration
+	Avg     time.Duration
+	Best    time.Duration
+	Worst   time.Duration
+	StdDev  time.Duration
+	// mean と m2 は Welford の方法で平均と分散を求めるための途中経過です
+	mean float64
+	m2   float64
+}
+
+func NewHopStatistics(hop int, artLine string) *HopStatistics {
+	return &HopStatistics{
+		Hop:     hop,
+		ArtLine: artLine,
+	}
+}
+
+// Observe は1回のプローブの結果を集計に加えます。応答元が変わった場合は最新の応答元を表示に使います。
+func (s *HopStatistics) Observe(hop *TraceHop) {
+	s.Sent++
+	if hop.IsTimeout() {
+		return
+	}
+
+	s.Recv++
+	s.Addr = hop.Addr
+	s.Name = hop.Name
+	s.Last = hop.Rtt
+	if s.Recv == 1 || hop.Rtt < s.Best {
+		s.Best = hop.Rtt
+	}
+	if hop.Rtt > s.Worst {
+		s.Worst = hop.Rtt
+	}
+
+	x := float64(hop.Rtt)
+	delta := x - s.mean
+	s.mean += delta / float64(s.Recv)
+	s.m2 += delta * (x - s.mean)
+	s.Avg = time.Duration(s.mean)
+	s.StdDev = time.Duration(math.Sqrt(s.m2 / float64(s.Recv)))
+}
+
+// Loss は応答のなかったプローブの割合(%)を返します。
+func (s *HopStatistics) Loss() float64 {
+	if s.Sent == 0 {
+		return 0
+	}
+	return float64(s.Sent-s.Recv) / float64(s.Sent) * 100
+}
+
+// RouteStatistics は宛先までの各ホップの HopStatistics です。
+// 宛先が応答したホップより先は経路に含めません。
+type RouteStatistics struct {
+	hops        []*HopStatistics
+	destination int
+}
+
+func NewRouteStatistics() *RouteStatistics {
+	return &RouteStatistics{}
+}
+
+// Observe は hop の結果を該当するホップの集計に加えます。
+func (r *RouteStatistics) Observe(hop *TraceHop) {
+	if r.destination > 0 && hop.Hop > r.destination {
+		return
+	}
+	for len(r.hops) < hop.Hop {
+		n := len(r.hops) + 1
+		r.hops = append(r.hops, NewHopStatistics(n, ""))
+	}
+	stats := r.hops[hop.Hop-1]
+	stats.ArtLine = hop.ArtLine
+	stats.Observe(hop)
+
+	if hop.IsLast() && (r.destination == 0 || hop.Hop < r.destination) {
+		r.destination = hop.Hop
+		r.hops = r.hops[:hop.Hop]
+	}
+}
+
+// Destination は宛先が応答したか到達不能と通知されたホップ番号を返します。まだ分からなければ0です。
+func (r *RouteStatistics) Destination() int {
+	return r.destination
+}
+
+// Hops はホップ番号順の集計の写しを返します。
+func (r *RouteStatistics) Hops() []HopStatistics {
+	hops := make([]HopStatistics, len(r.hops))
+	for i, stats := range r.hops {
+		hops[i] = *stats
+	}
+	return hops
+}
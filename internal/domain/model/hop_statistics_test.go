@@ -0,0 +1,78 @@
+package model
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestHopStatistics_Observe(t *testing.T) {
+	addr := net.IPv4(198, 51, 100, 1)
+	stats := NewHopStatistics(1, "line1")
+	for _, rtt := range []time.Duration{2 * time.Millisecond, 4 * time.Millisecond, 0, 6 * time.Millisecond} {
+		reply := &TraceReply{Addr: addr, Rtt: rtt}
+		if rtt == 0 {
+			reply = &TraceReply{}
+		}
+		stats.Observe(NewTraceHop(1, reply, "router1", "line1"))
+	}
+
+	if stats.Sent != 4 || stats.Recv != 3 {
+		t.Errorf("Sent, Recv = %d, %d, want 4, 3", stats.Sent, stats.Recv)
+	}
+	if got := stats.Loss(); got != 25 {
+		t.Errorf("Loss() = %v, want 25", got)
+	}
+	if stats.Last != 6*time.Millisecond || stats.Best != 2*time.Millisecond || stats.Worst != 6*time.Millisecond {
+		t.Errorf("Last, Best, Worst = %v, %v, %v", stats.Last, stats.Best, stats.Worst)
+	}
+	if stats.Avg != 4*time.Millisecond {
+		t.Errorf("Avg = %v, want 4ms", stats.Avg)
+	}
+	// 2,4,6ms の母標準偏差は sqrt(8/3)ms
+	if want := 1632993 * time.Nanosecond; stats.StdDev < want-time.Microsecond || stats.StdDev > want+time.Microsecond {
+		t.Errorf("StdDev = %v, want %v", stats.StdDev, want)
+	}
+	if !stats.Addr.Equal(addr) || stats.Name != "router1" {
+		t.Errorf("Addr, Name = %v, %q", stats.Addr, stats.Name)
+	}
+}
+
+func TestHopStatistics_Loss_NoProbes(t *testing.T) {
+	if got := NewHopStatistics(1, "").Loss(); got != 0 {
+		t.Errorf("Loss() = %v, want 0", got)
+	}
+}
+
+func TestRouteStatistics_Observe(t *testing.T) {
+	route := NewRouteStatistics()
+	router := &TraceReply{Addr: net.IPv4(198, 51, 100, 1), Rtt: time.Millisecond}
+	dest := &TraceReply{Addr: net.IPv4(192, 0, 2, 1), Rtt: time.Millisecond, Reached: true}
+
+	// 最初の巡では宛先より先のホップも調べている
+	route.Observe(NewTraceHop(1, router, "", "line1"))
+	route.Observe(NewTraceHop(3, dest, "", "line3"))
+	route.Observe(NewTraceHop(4, dest, "", "line1"))
+
+	if got := route.Destination(); got != 3 {
+		t.Errorf("Destination() = %d, want 3", got)
+	}
+	hops := route.Hops()
+	if len(hops) != 3 {
+		t.Fatalf("len(Hops()) = %d, want 3", len(hops))
+	}
+	for i, hop := range hops {
+		if hop.Hop != i+1 {
+			t.Errorf("hops[%d].Hop = %d", i, hop.Hop)
+		}
+	}
+	if hops[1].Sent != 0 {
+		t.Errorf("まだ調べていないホップの Sent = %d", hops[1].Sent)
+	}
+
+	// 写しを変更しても集計には影響しない
+	hops[0].Sent = 100
+	if route.Hops()[0].Sent != 1 {
+		t.Error("Hops() が写しを返していません")
+	}
+}
@@ -1,19 +1,85 @@
 package model
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 type PingConfig struct {
 	count      int
 	privileged bool
+	interval   time.Duration
+	timeout    time.Duration
+	deadline   time.Duration
+	size       int
+	ttl        int
+	pattern    []byte
+	family     IPFamily
+	protocol   Protocol
+	port       int
+	method     string
+	query      string
+	scenario   *SimulationScenario
 }
 
-func NewPingConfig(count int, privileged bool) (*PingConfig, error) {
+type Protocol int
+
+const (
+	ProtocolICMP Protocol = iota
+	ProtocolTCP
+	ProtocolHTTP
+	ProtocolUDP
+	ProtocolDNS
+	ProtocolSimulated
+)
+
+func (p Protocol) String() string {
+	switch p {
+	case ProtocolICMP:
+		return "icmp"
+	case ProtocolTCP:
+		return "tcp"
+	case ProtocolHTTP:
+		return "http"
+	case ProtocolUDP:
+		return "udp"
+	case ProtocolDNS:
+		return "dns"
+	case ProtocolSimulated:
+		return "simulated"
+	default:
+		return fmt.Sprintf("protocol(%d)", int(p))
+	}
+}
+
+const (
+	DefaultDNSPort    = 53
+	MaxPort           = 65535
+	MaxPacketSize     = 65507
+	MaxTTL            = 255
+	MaxPayloadPattern = 16
+)
+
+func NewPingConfig(count int, privileged bool, interval, timeout, deadline time.Duration) (*PingConfig, error) {
 	if count < 0 {
 		return nil, fmt.Errorf("count は0以上である必要があります: %d", count)
 	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("interval は0より大きい必要があります: %v", interval)
+	}
+	if timeout < 0 {
+		return nil, fmt.Errorf("timeout は0以上である必要があります: %v", timeout)
+	}
+	if deadline < 0 {
+		return nil, fmt.Errorf("deadline は0以上である必要があります: %v", deadline)
+	}
 	return &PingConfig{
 		count:      count,
 		privileged: privileged,
+		interval:   interval,
+		timeout:    timeout,
+		deadline:   deadline,
 	}, nil
 }
 
@@ -25,6 +91,30 @@ func (pc *PingConfig) Privileged() bool {
 	return pc.privileged
 }
 
+func (pc *PingConfig) Interval() time.Duration {
+	return pc.interval
+}
+
+func (pc *PingConfig) Timeout() time.Duration {
+	return pc.timeout
+}
+
+func (pc *PingConfig) Deadline() time.Duration {
+	return pc.deadline
+}
+
+// RunTimeout は最後のパケットの応答待ちと deadline のうち短い方を返します。0は無制限です。
+func (pc *PingConfig) RunTimeout() time.Duration {
+	var limit time.Duration
+	if pc.count > 0 && pc.timeout > 0 {
+		limit = pc.interval*time.Duration(pc.count-1) + pc.timeout
+	}
+	if pc.deadline > 0 && (limit == 0 || pc.deadline < limit) {
+		limit = pc.deadline
+	}
+	return limit
+}
+
 func (pc *PingConfig) SetCount(count int) error {
 	if count < 0 {
 		return fmt.Errorf("count は0以上である必要があります: %d", count)
@@ -32,3 +122,139 @@ func (pc *PingConfig) SetCount(count int) error {
 	pc.count = count
 	return nil
 }
+
+func (pc *PingConfig) Size() int {
+	return pc.size
+}
+
+func (pc *PingConfig) TTL() int {
+	return pc.ttl
+}
+
+func (pc *PingConfig) Pattern() []byte {
+	return pc.pattern
+}
+
+// SetSize はペイロードのバイト数を設定します。0の場合は各実装の既定値を使います。
+func (pc *PingConfig) SetSize(size int) error {
+	if size < 0 || size > MaxPacketSize {
+		return fmt.Errorf("size は0以上%d以下である必要があります: %d", MaxPacketSize, size)
+	}
+	pc.size = size
+	return nil
+}
+
+// SetTTL は送信パケットのTTL(ホップリミット)を設定します。0の場合は各実装の既定値を使います。
+func (pc *PingConfig) SetTTL(ttl int) error {
+	if ttl < 0 || ttl > MaxTTL {
+		return fmt.Errorf("ttl は0以上%d以下である必要があります: %d", MaxTTL, ttl)
+	}
+	pc.ttl = ttl
+	return nil
+}
+
+// SetPattern はペイロードを埋めるバイト列を設定します。
+func (pc *PingConfig) SetPattern(pattern []byte) error {
+	if len(pattern) > MaxPayloadPattern {
+		return fmt.Errorf("pattern は%dバイト以下である必要があります: %d", MaxPayloadPattern, len(pattern))
+	}
+	pc.pattern = pattern
+	return nil
+}
+
+func (pc *PingConfig) Family() IPFamily {
+	return pc.family
+}
+
+func (pc *PingConfig) SetFamily(family IPFamily) error {
+	if family < IPFamilyAny || family > IPFamilyV6 {
+		return fmt.Errorf("不明なアドレスファミリーです: %d", family)
+	}
+	pc.family = family
+	return nil
+}
+
+func (pc *PingConfig) Protocol() Protocol {
+	return pc.protocol
+}
+
+func (pc *PingConfig) Port() int {
+	return pc.port
+}
+
+// SetProtocol は計測に使うプロトコルと宛先ポートを設定します。
+// ICMPとHTTPではポートを使わず、DNSでは0を指定すると53番を使います。
+func (pc *PingConfig) SetProtocol(protocol Protocol, port int) error {
+	switch protocol {
+	case ProtocolICMP, ProtocolHTTP:
+		port = 0
+	case ProtocolDNS:
+		if port == 0 {
+			port = DefaultDNSPort
+		}
+		if port < 1 || port > MaxPort {
+			return fmt.Errorf("port は1以上%d以下である必要があります: %d", MaxPort, port)
+		}
+	case ProtocolTCP, ProtocolUDP:
+		if port < 1 || port > MaxPort {
+			return fmt.Errorf("port は1以上%d以下である必要があります: %d", MaxPort, port)
+		}
+	default:
+		return fmt.Errorf("不明なプロトコルです: %v", protocol)
+	}
+	pc.protocol = protocol
+	pc.port = port
+	return nil
+}
+
+// Method はHTTPモードで送るリクエストメソッドを返します。未設定の場合は HEAD です。
+func (pc *PingConfig) Method() string {
+	if pc.method == "" {
+		return "HEAD"
+	}
+	return pc.method
+}
+
+func (pc *PingConfig) SetMethod(method string) error {
+	switch method {
+	case "", "HEAD", "GET":
+		pc.method = method
+		return nil
+	default:
+		return fmt.Errorf("method は HEAD か GET である必要があります: %s", method)
+	}
+}
+
+// Query はDNSモードで問い合わせるドメイン名を返します。
+func (pc *PingConfig) Query() string {
+	return pc.query
+}
+
+func (pc *PingConfig) SetQuery(name string) error {
+	name = strings.TrimSuffix(name, ".")
+	if name == "" || len(name) > 253 {
+		return fmt.Errorf("query は1文字以上253文字以下のドメイン名である必要があります: %q", name)
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 {
+			return fmt.Errorf("query のラベルは1文字以上63文字以下である必要があります: %q", name)
+		}
+	}
+	pc.query = name
+	return nil
+}
+
+func (pc *PingConfig) Scenario() *SimulationScenario {
+	return pc.scenario
+}
+
+// SetSimulation は実際の通信の代わりに scenario に従って応答を再現するよう設定します。
+func (pc *PingConfig) SetSimulation(scenario *SimulationScenario) error {
+	if scenario == nil {
+		return fmt.Errorf("シミュレーションの筋書きが指定されていません")
+	}
+	pc.protocol = ProtocolSimulated
+	pc.port = 0
+	pc.scenario = scenario
+	return nil
+}
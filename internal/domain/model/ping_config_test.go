@@ -1,7 +1,9 @@
 package model
 
 import (
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewPingConfig(t *testing.T) {
@@ -9,31 +11,65 @@ func TestNewPingConfig(t *testing.T) {
 		name       string
 		count      int
 		privileged bool
+		interval   time.Duration
+		timeout    time.Duration
+		deadline   time.Duration
 		wantErr    bool
 	}{
 		{
 			name:       "有効な設定",
 			count:      10,
 			privileged: false,
+			interval:   time.Second,
 			wantErr:    false,
 		},
 		{
 			name:       "0回のPing",
 			count:      0,
 			privileged: true,
+			interval:   time.Second,
 			wantErr:    false,
 		},
 		{
 			name:       "負のカウント",
 			count:      -1,
 			privileged: false,
+			interval:   time.Second,
 			wantErr:    true,
 		},
+		{
+			name:     "タイムアウトとデッドライン指定",
+			count:    5,
+			interval: 200 * time.Millisecond,
+			timeout:  time.Second,
+			deadline: 10 * time.Second,
+			wantErr:  false,
+		},
+		{
+			name:     "0の送信間隔",
+			count:    1,
+			interval: 0,
+			wantErr:  true,
+		},
+		{
+			name:     "負のタイムアウト",
+			count:    1,
+			interval: time.Second,
+			timeout:  -time.Second,
+			wantErr:  true,
+		},
+		{
+			name:     "負のデッドライン",
+			count:    1,
+			interval: time.Second,
+			deadline: -time.Second,
+			wantErr:  true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			config, err := NewPingConfig(tt.count, tt.privileged)
+			config, err := NewPingConfig(tt.count, tt.privileged, tt.interval, tt.timeout, tt.deadline)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("NewPingConfig() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -45,13 +81,22 @@ func TestNewPingConfig(t *testing.T) {
 				if config.Privileged() != tt.privileged {
 					t.Errorf("Privileged() = %v, want %v", config.Privileged(), tt.privileged)
 				}
+				if config.Interval() != tt.interval {
+					t.Errorf("Interval() = %v, want %v", config.Interval(), tt.interval)
+				}
+				if config.Timeout() != tt.timeout {
+					t.Errorf("Timeout() = %v, want %v", config.Timeout(), tt.timeout)
+				}
+				if config.Deadline() != tt.deadline {
+					t.Errorf("Deadline() = %v, want %v", config.Deadline(), tt.deadline)
+				}
 			}
 		})
 	}
 }
 
 func TestPingConfig_SetCount(t *testing.T) {
-	config, _ := NewPingConfig(10, false)
+	config, _ := NewPingConfig(10, false, time.Second, 0, 0)
 
 	tests := []struct {
 		name     string
@@ -88,3 +133,343 @@ func TestPingConfig_SetCount(t *testing.T) {
 		})
 	}
 }
+
+func TestPingConfig_RunTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		count    int
+		timeout  time.Duration
+		deadline time.Duration
+		want     time.Duration
+	}{
+		{
+			name: "制限なし",
+			want: 0,
+		},
+		{
+			name:    "最後のパケットの応答待ちまで",
+			count:   5,
+			timeout: 2 * time.Second,
+			want:    6 * time.Second,
+		},
+		{
+			name:    "無限送信ではタイムアウトで打ち切らない",
+			count:   0,
+			timeout: 2 * time.Second,
+			want:    0,
+		},
+		{
+			name:     "デッドラインの方が短い",
+			count:    5,
+			timeout:  2 * time.Second,
+			deadline: 3 * time.Second,
+			want:     3 * time.Second,
+		},
+		{
+			name:     "デッドラインのみ",
+			deadline: 3 * time.Second,
+			want:     3 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := NewPingConfig(tt.count, false, time.Second, tt.timeout, tt.deadline)
+			if err != nil {
+				t.Fatalf("NewPingConfig() error = %v", err)
+			}
+			if got := config.RunTimeout(); got != tt.want {
+				t.Errorf("RunTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPingConfig_SetSize(t *testing.T) {
+	config, _ := NewPingConfig(10, false, time.Second, 0, 0)
+
+	tests := []struct {
+		name    string
+		size    int
+		wantErr bool
+	}{
+		{
+			name:    "既定値",
+			size:    0,
+			wantErr: false,
+		},
+		{
+			name:    "MTU相当",
+			size:    1472,
+			wantErr: false,
+		},
+		{
+			name:    "負のサイズ",
+			size:    -1,
+			wantErr: true,
+		},
+		{
+			name:    "上限超過",
+			size:    MaxPacketSize + 1,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := config.SetSize(tt.size)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SetSize() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && config.Size() != tt.size {
+				t.Errorf("Size() after SetSize = %v, want %v", config.Size(), tt.size)
+			}
+		})
+	}
+}
+
+func TestPingConfig_SetTTL(t *testing.T) {
+	config, _ := NewPingConfig(10, false, time.Second, 0, 0)
+
+	tests := []struct {
+		name    string
+		ttl     int
+		wantErr bool
+	}{
+		{
+			name:    "有効なTTL",
+			ttl:     3,
+			wantErr: false,
+		},
+		{
+			name:    "上限",
+			ttl:     MaxTTL,
+			wantErr: false,
+		},
+		{
+			name:    "上限超過",
+			ttl:     MaxTTL + 1,
+			wantErr: true,
+		},
+		{
+			name:    "負のTTL",
+			ttl:     -1,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := config.SetTTL(tt.ttl)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SetTTL() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && config.TTL() != tt.ttl {
+				t.Errorf("TTL() after SetTTL = %v, want %v", config.TTL(), tt.ttl)
+			}
+		})
+	}
+}
+
+func TestPingConfig_SetPattern(t *testing.T) {
+	config, _ := NewPingConfig(10, false, time.Second, 0, 0)
+
+	if err := config.SetPattern([]byte{0xff, 0x00}); err != nil {
+		t.Errorf("SetPattern() error = %v", err)
+	}
+	if got := config.Pattern(); len(got) != 2 || got[0] != 0xff {
+		t.Errorf("Pattern() = %v, want [255 0]", got)
+	}
+
+	if err := config.SetPattern(make([]byte, MaxPayloadPattern+1)); err == nil {
+		t.Error("SetPattern() 上限を超えるパターンでエラーが発生しませんでした")
+	}
+}
+
+func TestPingConfig_SetFamily(t *testing.T) {
+	config, _ := NewPingConfig(10, false, time.Second, 0, 0)
+
+	if config.Family() != IPFamilyAny {
+		t.Errorf("Family() default = %v, want %v", config.Family(), IPFamilyAny)
+	}
+	if err := config.SetFamily(IPFamilyV6); err != nil {
+		t.Errorf("SetFamily() error = %v", err)
+	}
+	if config.Family() != IPFamilyV6 {
+		t.Errorf("Family() after SetFamily = %v, want %v", config.Family(), IPFamilyV6)
+	}
+	if err := config.SetFamily(IPFamily(99)); err == nil {
+		t.Error("SetFamily() 不明なファミリーでエラーが発生しませんでした")
+	}
+}
+
+func TestPingConfig_SetProtocol(t *testing.T) {
+	tests := []struct {
+		name     string
+		protocol Protocol
+		port     int
+		wantPort int
+		wantErr  bool
+	}{
+		{
+			name:     "ICMP",
+			protocol: ProtocolICMP,
+			port:     443,
+			wantPort: 0,
+		},
+		{
+			name:     "TCP",
+			protocol: ProtocolTCP,
+			port:     443,
+			wantPort: 443,
+		},
+		{
+			name:     "HTTP",
+			protocol: ProtocolHTTP,
+			port:     8080,
+			wantPort: 0,
+		},
+		{
+			name:     "UDP",
+			protocol: ProtocolUDP,
+			port:     7,
+			wantPort: 7,
+		},
+		{
+			name:     "DNSでポート未指定",
+			protocol: ProtocolDNS,
+			port:     0,
+			wantPort: DefaultDNSPort,
+		},
+		{
+			name:     "UDPでポート未指定",
+			protocol: ProtocolUDP,
+			port:     0,
+			wantErr:  true,
+		},
+		{
+			name:     "TCPでポート未指定",
+			protocol: ProtocolTCP,
+			port:     0,
+			wantErr:  true,
+		},
+		{
+			name:     "TCPでポート範囲外",
+			protocol: ProtocolTCP,
+			port:     MaxPort + 1,
+			wantErr:  true,
+		},
+		{
+			name:     "不明なプロトコル",
+			protocol: Protocol(99),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, _ := NewPingConfig(10, false, time.Second, 0, 0)
+			err := config.SetProtocol(tt.protocol, tt.port)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SetProtocol() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if config.Protocol() != tt.protocol {
+				t.Errorf("Protocol() = %v, want %v", config.Protocol(), tt.protocol)
+			}
+			if config.Port() != tt.wantPort {
+				t.Errorf("Port() = %v, want %v", config.Port(), tt.wantPort)
+			}
+		})
+	}
+}
+
+func TestPingConfig_SetMethod(t *testing.T) {
+	config, _ := NewPingConfig(10, false, time.Second, 0, 0)
+
+	if got := config.Method(); got != "HEAD" {
+		t.Errorf("Method() default = %v, want HEAD", got)
+	}
+	if err := config.SetMethod("GET"); err != nil {
+		t.Errorf("SetMethod() error = %v", err)
+	}
+	if got := config.Method(); got != "GET" {
+		t.Errorf("Method() = %v, want GET", got)
+	}
+	if err := config.SetMethod("POST"); err == nil {
+		t.Error("SetMethod() 未対応のメソッドでエラーが発生しませんでした")
+	}
+}
+
+func TestPingConfig_SetQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "有効なドメイン名",
+			query: "example.tld",
+			want:  "example.tld",
+		},
+		{
+			name:  "末尾のドット",
+			query: "example.tld.",
+			want:  "example.tld",
+		},
+		{
+			name:    "空",
+			query:   "",
+			wantErr: true,
+		},
+		{
+			name:    "空のラベル",
+			query:   "example..tld",
+			wantErr: true,
+		},
+		{
+			name:    "長すぎるラベル",
+			query:   strings.Repeat("a", 64) + ".tld",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, _ := NewPingConfig(10, false, time.Second, 0, 0)
+			err := config.SetQuery(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SetQuery() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && config.Query() != tt.want {
+				t.Errorf("Query() = %v, want %v", config.Query(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPingConfig_SetSimulation(t *testing.T) {
+	config, _ := NewPingConfig(10, false, time.Second, 0, 0)
+
+	if err := config.SetSimulation(nil); err == nil {
+		t.Error("SetSimulation(nil) でエラーが発生しませんでした")
+	}
+
+	scenario := DefaultSimulationScenario(42)
+	if err := config.SetSimulation(scenario); err != nil {
+		t.Fatalf("SetSimulation() error = %v", err)
+	}
+	if config.Protocol() != ProtocolSimulated {
+		t.Errorf("Protocol() = %v, want %v", config.Protocol(), ProtocolSimulated)
+	}
+	if config.Scenario() != scenario {
+		t.Errorf("Scenario() = %v, want %v", config.Scenario(), scenario)
+	}
+}
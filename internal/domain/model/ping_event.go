@@ -0,0 +1,73 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+type PingEventType int
+
+const (
+	// PingEventTimeout は制限時間内に応答がなかったことを表します。
+	PingEventTimeout PingEventType = iota
+	// PingEventLost は接続拒否や2xx以外の応答など、応答が失敗だったことを表します。
+	PingEventLost
+	// PingEventSendError は送信そのものに失敗したことを表します。
+	PingEventSendError
+	// PingEventDuplicate は受信済みのシーケンスに重ねて応答が届いたことを表します。
+	PingEventDuplicate
+)
+
+func (t PingEventType) String() string {
+	switch t {
+	case PingEventTimeout:
+		return "timeout"
+	case PingEventLost:
+		return "lost"
+	case PingEventSendError:
+		return "send-error"
+	case PingEventDuplicate:
+		return "duplicate"
+	default:
+		return fmt.Sprintf("event(%d)", int(t))
+	}
+}
+
+func ParsePingEventType(s string) (PingEventType, error) {
+	for _, t := range []PingEventType{PingEventTimeout, PingEventLost, PingEventSendError, PingEventDuplicate} {
+		if t.String() == s {
+			return t, nil
+		}
+	}
+	return PingEventTimeout, fmt.Errorf("不明なイベントです: %s", s)
+}
+
+// PingEvent は受信以外の出来事を表します。Rtt は重複応答の場合のみ入ります。
+// StatusCode はHTTPモードで2xx以外の応答をロスとした場合のステータスコードです。
+type PingEvent struct {
+	Type       PingEventType
+	Seq        int
+	ArtLine    string
+	Rtt        time.Duration
+	StatusCode int
+	Err        error
+	// ArtColors と ArtBackgrounds は ArtLine の文字ごとの色と背景色です。色のないアートでは nil です。
+	ArtColors      []RGB
+	ArtBackgrounds []RGB
+	// Time は記録から再生したイベントの記録時刻です。計測中のイベントではゼロ値です。
+	Time time.Time
+}
+
+func NewPingEvent(eventType PingEventType, seq int, artLine string, err error) *PingEvent {
+	return &PingEvent{
+		Type:    eventType,
+		Seq:     seq,
+		ArtLine: artLine,
+		Err:     err,
+	}
+}
+
+// IsMissing はそのシーケンスの応答が得られなかったかどうかを返します。
+func (e *PingEvent) IsMissing() bool {
+	return e.Type == PingEventTimeout || e.Type == PingEventLost
+}
@@ -0,0 +1,36 @@
+package model
+
+import "testing"
+
+func TestPingEvent_IsMissing(t *testing.T) {
+	tests := []struct {
+		eventType PingEventType
+		want      bool
+	}{
+		{eventType: PingEventTimeout, want: true},
+		{eventType: PingEventLost, want: true},
+		{eventType: PingEventSendError, want: false},
+		{eventType: PingEventDuplicate, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.eventType.String(), func(t *testing.T) {
+			event := NewPingEvent(tt.eventType, 1, "line", nil)
+			if got := event.IsMissing(); got != tt.want {
+				t.Errorf("IsMissing() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePingEventType(t *testing.T) {
+	for _, eventType := range []PingEventType{PingEventTimeout, PingEventLost, PingEventSendError, PingEventDuplicate} {
+		got, err := ParsePingEventType(eventType.String())
+		if err != nil || got != eventType {
+			t.Errorf("ParsePingEventType(%q) = %v, %v, want %v", eventType.String(), got, err, eventType)
+		}
+	}
+	if _, err := ParsePingEventType("nyago"); err == nil {
+		t.Error("ParsePingEventType() 不明なイベントでエラーが発生しませんでした")
+	}
+}
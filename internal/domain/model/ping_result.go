@@ -1,17 +1,31 @@
 package model
 
 import (
+	"math"
 	"net"
+	"sort"
 	"time"
 )
 
+// DefaultHistogramBuckets は RTT ヒストグラムの既定の区間数です。
+const DefaultHistogramBuckets = 8
+
 type PingPacket struct {
-	Seq     int
-	Nbytes  int
-	IPAddr  net.IP
-	TTL     int
-	Rtt     time.Duration
-	ArtLine string
+	Seq        int
+	Nbytes     int
+	IPAddr     net.IP
+	TTL        int
+	Rtt        time.Duration
+	ArtLine    string
+	StatusCode int
+	// Level は RttThresholds による分類です。分類されていないパケットは LatencyUnknown になります。
+	Level LatencyLevel
+	// ArtColors は色付きのアートから取った ArtLine の文字ごとの色です。色のないアートでは nil です。
+	ArtColors []RGB
+	// ArtBackgrounds は ArtLine の文字ごとの背景色です。背景色のないアートでは nil です。
+	ArtBackgrounds []RGB
+	// Time は記録から再生したパケットの記録時刻です。計測中のパケットではゼロ値です。
+	Time time.Time
 }
 
 func NewPingPacket(seq, nbytes, ttl int, ipAddr net.IP, rtt time.Duration, artLine string) *PingPacket {
@@ -26,14 +40,31 @@ func NewPingPacket(seq, nbytes, ttl int, ipAddr net.IP, rtt time.Duration, artLi
 }
 
 type PingStatistics struct {
-	Addr        string
-	PacketsSent int
-	PacketsRecv int
-	PacketLoss  float64
-	MinRtt      time.Duration
-	AvgRtt      time.Duration
-	MaxRtt      time.Duration
-	StdDevRtt   time.Duration
+	Addr              string
+	PacketsSent       int
+	PacketsRecv       int
+	PacketsDuplicates int
+	PacketLoss        float64
+	MinRtt            time.Duration
+	AvgRtt            time.Duration
+	MaxRtt            time.Duration
+	StdDevRtt         time.Duration
+	P50Rtt            time.Duration
+	P90Rtt            time.Duration
+	P95Rtt            time.Duration
+	P99Rtt            time.Duration
+	// Jitter は RFC 3550 の到着間隔ジッタと同じ平滑化を、連続するRTTの差に適用した値です。
+	Jitter    time.Duration
+	Histogram []RttBucket
+	// Time は記録から再生した統計の記録時刻です。計測中の統計ではゼロ値です。
+	Time time.Time
+}
+
+// RttBucket はRTTヒストグラムの1区間です。Lower 以上 Upper 未満を数え、最後の区間だけ Upper を含みます。
+type RttBucket struct {
+	Lower time.Duration
+	Upper time.Duration
+	Count int
 }
 
 func NewPingStatistics(addr string, sent, recv int, loss float64, minRtt, avgRtt, maxRtt, stdDevRtt time.Duration) *PingStatistics {
@@ -48,3 +79,107 @@ func NewPingStatistics(addr string, sent, recv int, loss float64, minRtt, avgRtt
 		StdDevRtt:   stdDevRtt,
 	}
 }
+
+// CalculatePingStatistics は受信したパケットのRTTから統計を計算します。
+func CalculatePingStatistics(addr string, sent int, rtts []time.Duration) *PingStatistics {
+	recv := len(rtts)
+
+	var loss float64
+	if sent > 0 {
+		loss = float64(sent-recv) / float64(sent) * 100
+	}
+
+	if recv == 0 {
+		return NewPingStatistics(addr, sent, recv, loss, 0, 0, 0, 0)
+	}
+
+	minRtt, maxRtt := rtts[0], rtts[0]
+	var total time.Duration
+	for _, rtt := range rtts {
+		if rtt < minRtt {
+			minRtt = rtt
+		}
+		if rtt > maxRtt {
+			maxRtt = rtt
+		}
+		total += rtt
+	}
+	avgRtt := total / time.Duration(recv)
+
+	var sumSquares float64
+	for _, rtt := range rtts {
+		diff := float64(rtt - avgRtt)
+		sumSquares += diff * diff
+	}
+	stdDevRtt := time.Duration(math.Sqrt(sumSquares / float64(recv)))
+
+	stats := NewPingStatistics(addr, sent, recv, loss, minRtt, avgRtt, maxRtt, stdDevRtt)
+
+	sorted := make([]time.Duration, recv)
+	copy(sorted, rtts)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	stats.P50Rtt = percentile(sorted, 50)
+	stats.P90Rtt = percentile(sorted, 90)
+	stats.P95Rtt = percentile(sorted, 95)
+	stats.P99Rtt = percentile(sorted, 99)
+
+	stats.Jitter = calculateJitter(rtts)
+	stats.Histogram = NewRttHistogram(rtts, DefaultHistogramBuckets)
+
+	return stats
+}
+
+// percentile は昇順に並んだ RTT から最近傍順位法でパーセンタイルを求めます。
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// calculateJitter は受信順の RTT について J += (|D| - J) / 16 を繰り返します。
+func calculateJitter(rtts []time.Duration) time.Duration {
+	var jitter float64
+	for i := 1; i < len(rtts); i++ {
+		d := math.Abs(float64(rtts[i] - rtts[i-1]))
+		jitter += (d - jitter) / 16
+	}
+	return time.Duration(jitter)
+}
+
+// NewRttHistogram は最小値から最大値までを buckets 個の等幅区間に分けて RTT を数えます。
+// 全ての RTT が同じ場合は1区間だけを返します。
+func NewRttHistogram(rtts []time.Duration, buckets int) []RttBucket {
+	if len(rtts) == 0 || buckets <= 0 {
+		return nil
+	}
+
+	minRtt, maxRtt := rtts[0], rtts[0]
+	for _, rtt := range rtts {
+		minRtt = min(minRtt, rtt)
+		maxRtt = max(maxRtt, rtt)
+	}
+	if minRtt == maxRtt {
+		return []RttBucket{{Lower: minRtt, Upper: maxRtt, Count: len(rtts)}}
+	}
+
+	span := maxRtt - minRtt
+	histogram := make([]RttBucket, buckets)
+	for i := range histogram {
+		histogram[i].Lower = minRtt + span*time.Duration(i)/time.Duration(buckets)
+		histogram[i].Upper = minRtt + span*time.Duration(i+1)/time.Duration(buckets)
+	}
+
+	for _, rtt := range rtts {
+		i := int((rtt - minRtt) * time.Duration(buckets) / span)
+		if i >= buckets {
+			i = buckets - 1
+		}
+		histogram[i].Count++
+	}
+	return histogram
+}
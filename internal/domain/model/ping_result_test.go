@@ -0,0 +1,159 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalculatePingStatistics(t *testing.T) {
+	tests := []struct {
+		name       string
+		sent       int
+		rtts       []time.Duration
+		wantRecv   int
+		wantLoss   float64
+		wantMin    time.Duration
+		wantAvg    time.Duration
+		wantMax    time.Duration
+		wantStdDev time.Duration
+	}{
+		{
+			name:       "全て受信",
+			sent:       4,
+			rtts:       []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond, 40 * time.Millisecond},
+			wantRecv:   4,
+			wantLoss:   0,
+			wantMin:    10 * time.Millisecond,
+			wantAvg:    25 * time.Millisecond,
+			wantMax:    40 * time.Millisecond,
+			wantStdDev: time.Duration(11180339),
+		},
+		{
+			name:     "半分ロス",
+			sent:     4,
+			rtts:     []time.Duration{10 * time.Millisecond, 10 * time.Millisecond},
+			wantRecv: 2,
+			wantLoss: 50,
+			wantMin:  10 * time.Millisecond,
+			wantAvg:  10 * time.Millisecond,
+			wantMax:  10 * time.Millisecond,
+		},
+		{
+			name:     "全てロス",
+			sent:     3,
+			rtts:     nil,
+			wantRecv: 0,
+			wantLoss: 100,
+		},
+		{
+			name: "未送信",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := CalculatePingStatistics("example.tld", tt.sent, tt.rtts)
+			if stats.Addr != "example.tld" {
+				t.Errorf("Addr = %v, want example.tld", stats.Addr)
+			}
+			if stats.PacketsSent != tt.sent || stats.PacketsRecv != tt.wantRecv {
+				t.Errorf("Sent/Recv = %d/%d, want %d/%d", stats.PacketsSent, stats.PacketsRecv, tt.sent, tt.wantRecv)
+			}
+			if stats.PacketLoss != tt.wantLoss {
+				t.Errorf("PacketLoss = %v, want %v", stats.PacketLoss, tt.wantLoss)
+			}
+			if stats.MinRtt != tt.wantMin || stats.AvgRtt != tt.wantAvg || stats.MaxRtt != tt.wantMax {
+				t.Errorf("min/avg/max = %v/%v/%v, want %v/%v/%v", stats.MinRtt, stats.AvgRtt, stats.MaxRtt, tt.wantMin, tt.wantAvg, tt.wantMax)
+			}
+			if stats.StdDevRtt != tt.wantStdDev {
+				t.Errorf("StdDevRtt = %v, want %v", stats.StdDevRtt, tt.wantStdDev)
+			}
+		})
+	}
+}
+
+func TestCalculatePingStatistics_Distribution(t *testing.T) {
+	var rtts []time.Duration
+	for i := 1; i <= 100; i++ {
+		rtts = append(rtts, time.Duration(i)*time.Millisecond)
+	}
+
+	stats := CalculatePingStatistics("example.tld", 100, rtts)
+
+	if stats.P50Rtt != 50*time.Millisecond || stats.P90Rtt != 90*time.Millisecond || stats.P95Rtt != 95*time.Millisecond || stats.P99Rtt != 99*time.Millisecond {
+		t.Errorf("p50/p90/p95/p99 = %v/%v/%v/%v, want 50ms/90ms/95ms/99ms", stats.P50Rtt, stats.P90Rtt, stats.P95Rtt, stats.P99Rtt)
+	}
+
+	total := 0
+	for _, bucket := range stats.Histogram {
+		total += bucket.Count
+	}
+	if len(stats.Histogram) != DefaultHistogramBuckets || total != 100 {
+		t.Errorf("Histogram = %d区間 %d件, want %d区間 100件", len(stats.Histogram), total, DefaultHistogramBuckets)
+	}
+	if stats.Histogram[0].Lower != time.Millisecond || stats.Histogram[DefaultHistogramBuckets-1].Upper != 100*time.Millisecond {
+		t.Errorf("Histogram 範囲 = %v-%v, want 1ms-100ms", stats.Histogram[0].Lower, stats.Histogram[DefaultHistogramBuckets-1].Upper)
+	}
+}
+
+func TestCalculatePingStatistics_Jitter(t *testing.T) {
+	tests := []struct {
+		name string
+		rtts []time.Duration
+		want time.Duration
+	}{
+		{name: "1件", rtts: []time.Duration{10 * time.Millisecond}, want: 0},
+		{name: "一定", rtts: []time.Duration{10 * time.Millisecond, 10 * time.Millisecond, 10 * time.Millisecond}, want: 0},
+		// 16ms の差が1回で 1ms、続く 0ms の差で 1ms - 1ms/16
+		{name: "変動", rtts: []time.Duration{10 * time.Millisecond, 26 * time.Millisecond, 26 * time.Millisecond}, want: 937500 * time.Nanosecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := CalculatePingStatistics("example.tld", len(tt.rtts), tt.rtts)
+			if stats.Jitter != tt.want {
+				t.Errorf("Jitter = %v, want %v", stats.Jitter, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRttHistogram(t *testing.T) {
+	tests := []struct {
+		name    string
+		rtts    []time.Duration
+		buckets int
+		want    []RttBucket
+	}{
+		{name: "空", rtts: nil, buckets: 4, want: nil},
+		{
+			name:    "同じ値",
+			rtts:    []time.Duration{5 * time.Millisecond, 5 * time.Millisecond},
+			buckets: 4,
+			want:    []RttBucket{{Lower: 5 * time.Millisecond, Upper: 5 * time.Millisecond, Count: 2}},
+		},
+		{
+			name:    "最大値は最後の区間",
+			rtts:    []time.Duration{0, time.Millisecond, 3 * time.Millisecond, 4 * time.Millisecond},
+			buckets: 2,
+			want: []RttBucket{
+				{Lower: 0, Upper: 2 * time.Millisecond, Count: 2},
+				{Lower: 2 * time.Millisecond, Upper: 4 * time.Millisecond, Count: 2},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewRttHistogram(tt.rtts, tt.buckets)
+			if len(got) != len(tt.want) {
+				t.Fatalf("NewRttHistogram() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("bucket[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
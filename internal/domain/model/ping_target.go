@@ -3,11 +3,68 @@ package model
 import (
 	"fmt"
 	"net"
+	"strings"
 )
 
+type IPFamily int
+
+const (
+	IPFamilyAny IPFamily = iota
+	IPFamilyV4
+	IPFamilyV6
+)
+
+func NewIPFamily(forceIPv4, forceIPv6 bool) (IPFamily, error) {
+	switch {
+	case forceIPv4 && forceIPv6:
+		return IPFamilyAny, fmt.Errorf("IPv4とIPv6は同時に指定できません")
+	case forceIPv4:
+		return IPFamilyV4, nil
+	case forceIPv6:
+		return IPFamilyV6, nil
+	default:
+		return IPFamilyAny, nil
+	}
+}
+
+func IPFamilyOf(ip net.IP) IPFamily {
+	switch {
+	case ip == nil:
+		return IPFamilyAny
+	case ip.To4() != nil:
+		return IPFamilyV4
+	default:
+		return IPFamilyV6
+	}
+}
+
+// Network は net パッケージの名前解決で使うネットワーク名を返します。
+func (f IPFamily) Network() string {
+	switch f {
+	case IPFamilyV4:
+		return "ip4"
+	case IPFamilyV6:
+		return "ip6"
+	default:
+		return "ip"
+	}
+}
+
+func (f IPFamily) String() string {
+	switch f {
+	case IPFamilyV4:
+		return "IPv4"
+	case IPFamilyV6:
+		return "IPv6"
+	default:
+		return "any"
+	}
+}
+
 type PingTarget struct {
-	host string
-	ip   net.IP
+	host   string
+	ip     net.IP
+	family IPFamily
 }
 
 func NewPingTarget(host string) (*PingTarget, error) {
@@ -23,10 +80,21 @@ func (pt *PingTarget) Host() string {
 	return pt.host
 }
 
+// IsURL はホストが http:// または https:// で始まるURLかどうかを返します。
+func (pt *PingTarget) IsURL() bool {
+	return strings.HasPrefix(pt.host, "http://") || strings.HasPrefix(pt.host, "https://")
+}
+
 func (pt *PingTarget) IP() net.IP {
 	return pt.ip
 }
 
+// Family は名前解決されたアドレスファミリーを返します。未解決の場合は IPFamilyAny です。
+func (pt *PingTarget) Family() IPFamily {
+	return pt.family
+}
+
 func (pt *PingTarget) SetIP(ip net.IP) {
 	pt.ip = ip
+	pt.family = IPFamilyOf(ip)
 }
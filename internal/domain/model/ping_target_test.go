@@ -1,6 +1,7 @@
 package model
 
 import (
+	"net"
 	"testing"
 )
 
@@ -44,3 +45,115 @@ func TestPingTarget_Host(t *testing.T) {
 		t.Errorf("Host() = %v, want %v", got, host)
 	}
 }
+
+func TestPingTarget_SetIP_Family(t *testing.T) {
+	tests := []struct {
+		name string
+		ip   net.IP
+		want IPFamily
+	}{
+		{
+			name: "IPv4",
+			ip:   net.ParseIP("192.0.2.1"),
+			want: IPFamilyV4,
+		},
+		{
+			name: "IPv6",
+			ip:   net.ParseIP("2001:db8::1"),
+			want: IPFamilyV6,
+		},
+		{
+			name: "IPv4射影IPv6アドレス",
+			ip:   net.ParseIP("::ffff:192.0.2.1"),
+			want: IPFamilyV4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, _ := NewPingTarget("example.tld")
+			if target.Family() != IPFamilyAny {
+				t.Errorf("Family() before SetIP = %v, want %v", target.Family(), IPFamilyAny)
+			}
+			target.SetIP(tt.ip)
+			if got := target.Family(); got != tt.want {
+				t.Errorf("Family() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewIPFamily(t *testing.T) {
+	tests := []struct {
+		name        string
+		forceIPv4   bool
+		forceIPv6   bool
+		want        IPFamily
+		wantNetwork string
+		wantErr     bool
+	}{
+		{
+			name:        "指定なし",
+			want:        IPFamilyAny,
+			wantNetwork: "ip",
+		},
+		{
+			name:        "IPv4固定",
+			forceIPv4:   true,
+			want:        IPFamilyV4,
+			wantNetwork: "ip4",
+		},
+		{
+			name:        "IPv6固定",
+			forceIPv6:   true,
+			want:        IPFamilyV6,
+			wantNetwork: "ip6",
+		},
+		{
+			name:      "両方指定",
+			forceIPv4: true,
+			forceIPv6: true,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			family, err := NewIPFamily(tt.forceIPv4, tt.forceIPv6)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewIPFamily() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if family != tt.want {
+				t.Errorf("NewIPFamily() = %v, want %v", family, tt.want)
+			}
+			if got := family.Network(); got != tt.wantNetwork {
+				t.Errorf("Network() = %v, want %v", got, tt.wantNetwork)
+			}
+		})
+	}
+}
+
+func TestPingTarget_IsURL(t *testing.T) {
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{host: "example.tld", want: false},
+		{host: "http://example.tld/", want: true},
+		{host: "https://example.tld/health", want: true},
+		{host: "ftp://example.tld/", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			target, _ := NewPingTarget(tt.host)
+			if got := target.IsURL(); got != tt.want {
+				t.Errorf("IsURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// LatencyLevel はRTTを閾値で分類した結果です。ゼロ値は未分類を表します。
+type LatencyLevel int
+
+const (
+	LatencyUnknown LatencyLevel = iota
+	LatencyGood
+	LatencyWarn
+	LatencyBad
+)
+
+func (l LatencyLevel) String() string {
+	switch l {
+	case LatencyGood:
+		return "good"
+	case LatencyWarn:
+		return "warn"
+	case LatencyBad:
+		return "bad"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	DefaultWarnRtt = 30 * time.Millisecond
+	DefaultBadRtt  = 100 * time.Millisecond
+)
+
+// RttThresholds は warn 未満を良好、bad 未満を注意、それ以上を危険として分類します。
+type RttThresholds struct {
+	warn time.Duration
+	bad  time.Duration
+}
+
+func NewRttThresholds(warn, bad time.Duration) (*RttThresholds, error) {
+	if warn <= 0 || bad <= 0 {
+		return nil, fmt.Errorf("RTTの閾値は0より大きい必要があります: warn=%v, bad=%v", warn, bad)
+	}
+	if warn >= bad {
+		return nil, fmt.Errorf("warn の閾値は bad より小さい必要があります: warn=%v, bad=%v", warn, bad)
+	}
+	return &RttThresholds{
+		warn: warn,
+		bad:  bad,
+	}, nil
+}
+
+func DefaultRttThresholds() *RttThresholds {
+	return &RttThresholds{
+		warn: DefaultWarnRtt,
+		bad:  DefaultBadRtt,
+	}
+}
+
+func (t *RttThresholds) Warn() time.Duration {
+	return t.warn
+}
+
+func (t *RttThresholds) Bad() time.Duration {
+	return t.bad
+}
+
+func (t *RttThresholds) Classify(rtt time.Duration) LatencyLevel {
+	switch {
+	case rtt < t.warn:
+		return LatencyGood
+	case rtt < t.bad:
+		return LatencyWarn
+	default:
+		return LatencyBad
+	}
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewRttThresholds(t *testing.T) {
+	tests := []struct {
+		name    string
+		warn    time.Duration
+		bad     time.Duration
+		wantErr bool
+	}{
+		{name: "正常", warn: 30 * time.Millisecond, bad: 100 * time.Millisecond},
+		{name: "ゼロ", warn: 0, bad: 100 * time.Millisecond, wantErr: true},
+		{name: "負", warn: 30 * time.Millisecond, bad: -time.Millisecond, wantErr: true},
+		{name: "逆転", warn: 100 * time.Millisecond, bad: 30 * time.Millisecond, wantErr: true},
+		{name: "同じ", warn: 50 * time.Millisecond, bad: 50 * time.Millisecond, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRttThresholds(tt.warn, tt.bad)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewRttThresholds() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRttThresholds_Classify(t *testing.T) {
+	thresholds := DefaultRttThresholds()
+
+	tests := []struct {
+		rtt  time.Duration
+		want LatencyLevel
+	}{
+		{rtt: time.Millisecond, want: LatencyGood},
+		{rtt: 30 * time.Millisecond, want: LatencyWarn},
+		{rtt: 99 * time.Millisecond, want: LatencyWarn},
+		{rtt: 100 * time.Millisecond, want: LatencyBad},
+		{rtt: time.Second, want: LatencyBad},
+	}
+
+	for _, tt := range tests {
+		if got := thresholds.Classify(tt.rtt); got != tt.want {
+			t.Errorf("Classify(%v) = %v, want %v", tt.rtt, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// SessionRecord は記録されたセッションの1件分です。Packet・Event・Statistics のいずれか1つが入ります。
+type SessionRecord struct {
+	Time       time.Time
+	Host       string
+	Packet     *PingPacket
+	Event      *PingEvent
+	Statistics *PingStatistics
+}
+
+// Session は記録されたPingの実行を時刻順に保持します。
+type Session struct {
+	records []*SessionRecord
+}
+
+func NewSession(records []*SessionRecord) *Session {
+	return &Session{
+		records: records,
+	}
+}
+
+func (s *Session) Records() []*SessionRecord {
+	return s.records
+}
+
+// Hosts は記録に現れたホストを最初に現れた順に返します。
+func (s *Session) Hosts() []string {
+	var hosts []string
+	seen := make(map[string]bool)
+	for _, record := range s.records {
+		if !seen[record.Host] {
+			seen[record.Host] = true
+			hosts = append(hosts, record.Host)
+		}
+	}
+	return hosts
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSession_Hosts(t *testing.T) {
+	now := time.Now()
+	session := NewSession([]*SessionRecord{
+		{Time: now, Host: "b.tld", Packet: &PingPacket{Seq: 0}},
+		{Time: now, Host: "a.tld", Packet: &PingPacket{Seq: 0}},
+		{Time: now, Host: "b.tld", Packet: &PingPacket{Seq: 1}},
+		{Time: now, Host: "a.tld", Statistics: &PingStatistics{}},
+	})
+
+	hosts := session.Hosts()
+	want := []string{"b.tld", "a.tld"}
+	if len(hosts) != len(want) {
+		t.Fatalf("Hosts() = %v, want %v", hosts, want)
+	}
+	for i := range want {
+		if hosts[i] != want[i] {
+			t.Errorf("Hosts()[%d] = %v, want %v", i, hosts[i], want[i])
+		}
+	}
+}
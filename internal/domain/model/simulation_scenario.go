@@ -0,0 +1,168 @@
+package model
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+type RttDistribution int
+
+const (
+	RttDistributionNormal RttDistribution = iota
+	RttDistributionUniform
+)
+
+func ParseRttDistribution(s string) (RttDistribution, error) {
+	switch s {
+	case "", "normal":
+		return RttDistributionNormal, nil
+	case "uniform":
+		return RttDistributionUniform, nil
+	default:
+		return RttDistributionNormal, fmt.Errorf("不明なRTT分布です: %s", s)
+	}
+}
+
+func (d RttDistribution) String() string {
+	switch d {
+	case RttDistributionUniform:
+		return "uniform"
+	default:
+		return "normal"
+	}
+}
+
+// LossBurst は Start 番目から Length 個のシーケンスが連続してロスすることを表します。
+type LossBurst struct {
+	Start  int
+	Length int
+}
+
+// SimulationScenario はネットワークを使わずにPingを再現するための筋書きです。
+// RTTは RttMean を中心に RttJitter の幅(normal では標準偏差、uniform では±の幅)でばらつきます。
+type SimulationScenario struct {
+	seed          int64
+	distribution  RttDistribution
+	rttMean       time.Duration
+	rttJitter     time.Duration
+	lossRate      float64
+	lossBursts    []LossBurst
+	duplicateRate float64
+	rnd           *rand.Rand
+}
+
+func NewSimulationScenario(
+	seed int64,
+	distribution RttDistribution,
+	rttMean, rttJitter time.Duration,
+	lossRate float64,
+	lossBursts []LossBurst,
+	duplicateRate float64,
+) (*SimulationScenario, error) {
+	if rttMean <= 0 {
+		return nil, fmt.Errorf("rtt の平均は0より大きい必要があります: %v", rttMean)
+	}
+	if rttJitter < 0 {
+		return nil, fmt.Errorf("rtt のジッターは0以上である必要があります: %v", rttJitter)
+	}
+	if lossRate < 0 || lossRate > 1 {
+		return nil, fmt.Errorf("loss は0以上1以下である必要があります: %v", lossRate)
+	}
+	if duplicateRate < 0 || duplicateRate > 1 {
+		return nil, fmt.Errorf("duplicate は0以上1以下である必要があります: %v", duplicateRate)
+	}
+	for _, burst := range lossBursts {
+		if burst.Start < 0 || burst.Length <= 0 {
+			return nil, fmt.Errorf("ロスバーストの範囲が不正です: start=%d length=%d", burst.Start, burst.Length)
+		}
+	}
+	return &SimulationScenario{
+		seed:          seed,
+		distribution:  distribution,
+		rttMean:       rttMean,
+		rttJitter:     rttJitter,
+		lossRate:      lossRate,
+		lossBursts:    lossBursts,
+		duplicateRate: duplicateRate,
+	}, nil
+}
+
+// DefaultSimulationScenario は平均20ms、ばらつき5ms、ロス率2%の筋書きを返します。
+func DefaultSimulationScenario(seed int64) *SimulationScenario {
+	scenario, _ := NewSimulationScenario(seed, RttDistributionNormal, 20*time.Millisecond, 5*time.Millisecond, 0.02, nil, 0)
+	return scenario
+}
+
+func (s *SimulationScenario) Seed() int64 {
+	return s.seed
+}
+
+// SetSeed はシードを変え、Rand の乱数を最初から引き直します。
+func (s *SimulationScenario) SetSeed(seed int64) {
+	s.seed = seed
+	s.rnd = nil
+}
+
+// Rand は筋書きの乱数を返します。同じ筋書きで繰り返し計測すると、前回の続きから引きます。
+// シードが0なら現在時刻をシードにします。複数のゴルーチンから同時に使うことはできません。
+func (s *SimulationScenario) Rand() *rand.Rand {
+	if s.rnd == nil {
+		seed := s.seed
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+		s.rnd = rand.New(rand.NewSource(seed))
+	}
+	return s.rnd
+}
+
+// LaneSeed は複数ホストを同時に計測するときの lane 番目のホストのシードを seed から作ります。
+// splitmix64 で seed と lane を混ぜるため、隣り合うシードの間でホストの乱数の並びが重なりません。lane 0 は seed のままです。
+func LaneSeed(seed int64, lane int) int64 {
+	if lane == 0 {
+		return seed
+	}
+	return int64(splitmix64(splitmix64(uint64(seed)) + uint64(lane)))
+}
+
+func splitmix64(z uint64) uint64 {
+	z += 0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+func (s *SimulationScenario) Distribution() RttDistribution {
+	return s.distribution
+}
+
+func (s *SimulationScenario) RttMean() time.Duration {
+	return s.rttMean
+}
+
+func (s *SimulationScenario) RttJitter() time.Duration {
+	return s.rttJitter
+}
+
+func (s *SimulationScenario) LossRate() float64 {
+	return s.lossRate
+}
+
+func (s *SimulationScenario) LossBursts() []LossBurst {
+	return s.lossBursts
+}
+
+func (s *SimulationScenario) DuplicateRate() float64 {
+	return s.duplicateRate
+}
+
+// InLossBurst は seq がいずれかのロスバーストに含まれるかを返します。
+func (s *SimulationScenario) InLossBurst(seq int) bool {
+	for _, burst := range s.lossBursts {
+		if seq >= burst.Start && seq < burst.Start+burst.Length {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestNewSimulationScenario(t *testing.T) {
+	tests := []struct {
+		name      string
+		rttMean   time.Duration
+		rttJitter time.Duration
+		loss      float64
+		bursts    []LossBurst
+		duplicate float64
+		wantErr   bool
+	}{
+		{
+			name:    "有効な筋書き",
+			rttMean: 20 * time.Millisecond,
+			loss:    0.1,
+			bursts:  []LossBurst{{Start: 3, Length: 2}},
+		},
+		{
+			name:    "平均RTTが0",
+			rttMean: 0,
+			wantErr: true,
+		},
+		{
+			name:      "負のジッター",
+			rttMean:   time.Millisecond,
+			rttJitter: -time.Millisecond,
+			wantErr:   true,
+		},
+		{
+			name:    "ロス率が1超",
+			rttMean: time.Millisecond,
+			loss:    1.5,
+			wantErr: true,
+		},
+		{
+			name:      "負の重複率",
+			rttMean:   time.Millisecond,
+			duplicate: -0.1,
+			wantErr:   true,
+		},
+		{
+			name:    "長さ0のバースト",
+			rttMean: time.Millisecond,
+			bursts:  []LossBurst{{Start: 1, Length: 0}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSimulationScenario(1, RttDistributionNormal, tt.rttMean, tt.rttJitter, tt.loss, tt.bursts, tt.duplicate)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewSimulationScenario() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSimulationScenario_InLossBurst(t *testing.T) {
+	scenario, _ := NewSimulationScenario(1, RttDistributionNormal, time.Millisecond, 0, 0, []LossBurst{{Start: 3, Length: 2}, {Start: 10, Length: 1}}, 0)
+
+	tests := []struct {
+		seq  int
+		want bool
+	}{
+		{seq: 2, want: false},
+		{seq: 3, want: true},
+		{seq: 4, want: true},
+		{seq: 5, want: false},
+		{seq: 10, want: true},
+	}
+
+	for _, tt := range tests {
+		if got := scenario.InLossBurst(tt.seq); got != tt.want {
+			t.Errorf("InLossBurst(%d) = %v, want %v", tt.seq, got, tt.want)
+		}
+	}
+}
+
+func TestParseRttDistribution(t *testing.T) {
+	for _, s := range []string{"", "normal", "uniform"} {
+		if _, err := ParseRttDistribution(s); err != nil {
+			t.Errorf("ParseRttDistribution(%q) error = %v", s, err)
+		}
+	}
+	if _, err := ParseRttDistribution("poisson"); err == nil {
+		t.Error("ParseRttDistribution() 不明な分布でエラーが発生しませんでした")
+	}
+}
+
+func TestSimulationScenario_Rand(t *testing.T) {
+	scenario := DefaultSimulationScenario(42)
+	first, second := scenario.Rand().Int63(), scenario.Rand().Int63()
+	if first == second {
+		t.Error("Rand() が続きから引いていません")
+	}
+
+	// シードを変えると最初から引き直す
+	scenario.SetSeed(42)
+	if got := scenario.Rand().Int63(); got != first {
+		t.Errorf("SetSeed() 後の最初の値 = %d, want %d", got, first)
+	}
+}
+
+func TestLaneSeed(t *testing.T) {
+	if got := LaneSeed(42, 0); got != 42 {
+		t.Errorf("LaneSeed(42, 0) = %d, want 42", got)
+	}
+
+	// 隣り合うシードのホスト同士でシードが重ならない
+	seen := make(map[int64]string)
+	for seed := int64(1); seed <= 8; seed++ {
+		for lane := 0; lane < 8; lane++ {
+			got := LaneSeed(seed, lane)
+			key := fmt.Sprintf("seed=%d lane=%d", seed, lane)
+			if other, ok := seen[got]; ok {
+				t.Errorf("%s と %s のシードが同じです: %d", key, other, got)
+			}
+			seen[got] = key
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package model
+
+import "time"
+
+// DefaultRttBuckets は監視モードの RTT ヒストグラムの上限値です。
+var DefaultRttBuckets = []time.Duration{
+	time.Millisecond,
+	2500 * time.Microsecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+}
+
+// TargetMetrics は1ホストについて監視を始めてからの累計を保持します。
+// Probes は応答・ロス・送信失敗のいずれかで結果が確定したプローブの数で、Replies + Lost + SendErrors と一致します。
+// RttCounts[i] は RttBuckets[i] 以下の RTT の件数で、Prometheus のヒストグラムと同じく累積です。
+type TargetMetrics struct {
+	Host       string
+	Probes     uint64
+	Replies    uint64
+	Lost       uint64
+	Duplicates uint64
+	SendErrors uint64
+	Failures   uint64
+	RttBuckets []time.Duration
+	RttCounts  []uint64
+	RttSum     time.Duration
+	LastRtt    time.Duration
+	// Up は直近のプローブに応答があったかどうかです。
+	Up bool
+}
+
+func NewTargetMetrics(host string, buckets []time.Duration) *TargetMetrics {
+	return &TargetMetrics{
+		Host:       host,
+		RttBuckets: buckets,
+		RttCounts:  make([]uint64, len(buckets)),
+	}
+}
+
+func (m *TargetMetrics) ObservePacket(packet *PingPacket) {
+	m.Probes++
+	m.Replies++
+	m.RttSum += packet.Rtt
+	m.LastRtt = packet.Rtt
+	m.Up = true
+	for i, upper := range m.RttBuckets {
+		if packet.Rtt <= upper {
+			m.RttCounts[i]++
+		}
+	}
+}
+
+func (m *TargetMetrics) ObserveEvent(event *PingEvent) {
+	switch {
+	case event.IsMissing():
+		m.Probes++
+		m.Lost++
+		m.Up = false
+	case event.Type == PingEventDuplicate:
+		m.Duplicates++
+	case event.Type == PingEventSendError:
+		// 送信に失敗したプローブには応答もタイムアウトも続かないため、ここで1件として数える
+		m.Probes++
+		m.SendErrors++
+		m.Up = false
+	}
+}
+
+// ObserveFailure は名前解決の失敗などで計測自体ができなかったことを記録します。
+func (m *TargetMetrics) ObserveFailure() {
+	m.Failures++
+	m.Up = false
+}
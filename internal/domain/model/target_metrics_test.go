@@ -0,0 +1,42 @@
+package model
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestTargetMetrics_Observe(t *testing.T) {
+	m := NewTargetMetrics("example.tld", []time.Duration{10 * time.Millisecond, 100 * time.Millisecond})
+
+	m.ObservePacket(NewPingPacket(0, 64, 64, net.IPv4(192, 0, 2, 1), 5*time.Millisecond, "line1"))
+	m.ObservePacket(NewPingPacket(1, 64, 64, net.IPv4(192, 0, 2, 1), 50*time.Millisecond, "line2"))
+	if !m.Up {
+		t.Error("Up = false, want true")
+	}
+	m.ObserveEvent(NewPingEvent(PingEventDuplicate, 1, "line2", nil))
+	m.ObserveEvent(NewPingEvent(PingEventTimeout, 2, "line3", nil))
+	m.ObserveEvent(NewPingEvent(PingEventSendError, 3, "line4", nil))
+
+	if m.Probes != 4 || m.Replies != 2 || m.Lost != 1 || m.Duplicates != 1 || m.SendErrors != 1 {
+		t.Errorf("probes/replies/lost/dup/sendErr = %d/%d/%d/%d/%d, want 4/2/1/1/1", m.Probes, m.Replies, m.Lost, m.Duplicates, m.SendErrors)
+	}
+	if m.RttCounts[0] != 1 || m.RttCounts[1] != 2 {
+		t.Errorf("RttCounts = %v, want [1 2]", m.RttCounts)
+	}
+	if m.RttSum != 55*time.Millisecond || m.LastRtt != 50*time.Millisecond {
+		t.Errorf("RttSum/LastRtt = %v/%v, want 55ms/50ms", m.RttSum, m.LastRtt)
+	}
+	if m.Up {
+		t.Error("タイムアウト後も Up = true です")
+	}
+	if m.Probes != m.Replies+m.Lost+m.SendErrors {
+		t.Errorf("Probes = %d, want Replies + Lost + SendErrors = %d", m.Probes, m.Replies+m.Lost+m.SendErrors)
+	}
+
+	m.ObservePacket(NewPingPacket(4, 64, 64, net.IPv4(192, 0, 2, 1), time.Millisecond, "line5"))
+	m.ObserveFailure()
+	if m.Up || m.Failures != 1 {
+		t.Errorf("Up/Failures = %v/%d, want false/1", m.Up, m.Failures)
+	}
+}
@@ -0,0 +1,116 @@
+package model
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// DefaultMaxHops は宛先に届かない場合に経路の調査を打ち切るホップ数の既定値です。
+const DefaultMaxHops = 30
+
+// TraceConfig は TTL を1つずつ増やしながら経路上のルーターを調べる設定です。
+type TraceConfig struct {
+	maxHops  int
+	timeout  time.Duration
+	family   IPFamily
+	protocol Protocol
+	scenario *SimulationScenario
+}
+
+func NewTraceConfig(maxHops int, timeout time.Duration) (*TraceConfig, error) {
+	if maxHops < 1 || maxHops > MaxTTL {
+		return nil, fmt.Errorf("max-hops は1以上%d以下である必要があります: %d", MaxTTL, maxHops)
+	}
+	if timeout <= 0 {
+		return nil, fmt.Errorf("timeout は0より大きい必要があります: %v", timeout)
+	}
+	return &TraceConfig{
+		maxHops:  maxHops,
+		timeout:  timeout,
+		protocol: ProtocolICMP,
+	}, nil
+}
+
+func (tc *TraceConfig) MaxHops() int {
+	return tc.maxHops
+}
+
+// Timeout は1ホップあたりの応答待ち時間です。
+func (tc *TraceConfig) Timeout() time.Duration {
+	return tc.timeout
+}
+
+func (tc *TraceConfig) Family() IPFamily {
+	return tc.family
+}
+
+func (tc *TraceConfig) SetFamily(family IPFamily) error {
+	if family < IPFamilyAny || family > IPFamilyV6 {
+		return fmt.Errorf("不明なアドレスファミリーです: %d", family)
+	}
+	tc.family = family
+	return nil
+}
+
+func (tc *TraceConfig) Protocol() Protocol {
+	return tc.protocol
+}
+
+func (tc *TraceConfig) Scenario() *SimulationScenario {
+	return tc.scenario
+}
+
+// SetSimulation は実際の通信の代わりに scenario に従って経路を再現するよう設定します。
+func (tc *TraceConfig) SetSimulation(scenario *SimulationScenario) error {
+	if scenario == nil {
+		return fmt.Errorf("シミュレーションの筋書きが指定されていません")
+	}
+	tc.protocol = ProtocolSimulated
+	tc.scenario = scenario
+	return nil
+}
+
+// TraceReply は TTL を制限した1回のプローブへの応答です。
+// Addr が nil の場合は応答がありませんでした。
+type TraceReply struct {
+	Addr net.IP
+	Rtt  time.Duration
+	// Reached は宛先自身が応答したことを表します。
+	Reached bool
+	// Unreachable は途中のルーターから宛先に到達できないと通知されたことを表します。
+	Unreachable bool
+}
+
+// TraceHop は経路上の1ホップ分の結果です。
+type TraceHop struct {
+	Hop         int
+	Addr        net.IP
+	Name        string
+	Rtt         time.Duration
+	ArtLine     string
+	Reached     bool
+	Unreachable bool
+}
+
+func NewTraceHop(hop int, reply *TraceReply, name, artLine string) *TraceHop {
+	return &TraceHop{
+		Hop:         hop,
+		Addr:        reply.Addr,
+		Name:        name,
+		Rtt:         reply.Rtt,
+		ArtLine:     artLine,
+		Reached:     reply.Reached,
+		Unreachable: reply.Unreachable,
+	}
+}
+
+// IsTimeout はこのホップから応答がなかったかどうかを返します。
+func (h *TraceHop) IsTimeout() bool {
+	return h.Addr == nil
+}
+
+// IsLast はこのホップで経路の調査を終えるべきかどうかを返します。
+func (h *TraceHop) IsLast() bool {
+	return h.Reached || h.Unreachable
+}
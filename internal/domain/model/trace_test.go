@@ -0,0 +1,84 @@
+package model
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestNewTraceConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxHops int
+		timeout time.Duration
+		wantErr bool
+	}{
+		{"既定値", DefaultMaxHops, time.Second, false},
+		{"最大", MaxTTL, time.Second, false},
+		{"ホップ数0", 0, time.Second, true},
+		{"ホップ数超過", MaxTTL + 1, time.Second, true},
+		{"待ち時間0", DefaultMaxHops, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := NewTraceConfig(tt.maxHops, tt.timeout)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewTraceConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && config.Protocol() != ProtocolICMP {
+				t.Errorf("Protocol() = %v, want %v", config.Protocol(), ProtocolICMP)
+			}
+		})
+	}
+}
+
+func TestTraceConfig_SetSimulation(t *testing.T) {
+	config, _ := NewTraceConfig(DefaultMaxHops, time.Second)
+	if err := config.SetSimulation(nil); err == nil {
+		t.Error("SetSimulation(nil) でエラーが発生しませんでした")
+	}
+	if err := config.SetSimulation(DefaultSimulationScenario(1)); err != nil {
+		t.Fatalf("SetSimulation() error = %v", err)
+	}
+	if config.Protocol() != ProtocolSimulated {
+		t.Errorf("Protocol() = %v, want %v", config.Protocol(), ProtocolSimulated)
+	}
+}
+
+func TestTraceConfig_SetFamily(t *testing.T) {
+	config, _ := NewTraceConfig(30, time.Second)
+	if err := config.SetFamily(IPFamilyV6); err != nil {
+		t.Errorf("SetFamily() error = %v", err)
+	}
+	if config.Family() != IPFamilyV6 {
+		t.Errorf("Family() = %v, want %v", config.Family(), IPFamilyV6)
+	}
+	if err := config.SetFamily(IPFamily(99)); err == nil {
+		t.Error("SetFamily() 不明なファミリーでエラーが発生しませんでした")
+	}
+}
+
+func TestTraceHop(t *testing.T) {
+	tests := []struct {
+		name        string
+		reply       *TraceReply
+		wantTimeout bool
+		wantLast    bool
+	}{
+		{"ルーター", &TraceReply{Addr: net.IPv4(198, 51, 100, 1)}, false, false},
+		{"応答なし", &TraceReply{}, true, false},
+		{"宛先", &TraceReply{Addr: net.IPv4(192, 0, 2, 1), Reached: true}, false, true},
+		{"到達不能", &TraceReply{Addr: net.IPv4(198, 51, 100, 1), Unreachable: true}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hop := NewTraceHop(1, tt.reply, "", "line1")
+			if hop.IsTimeout() != tt.wantTimeout {
+				t.Errorf("IsTimeout() = %v, want %v", hop.IsTimeout(), tt.wantTimeout)
+			}
+			if hop.IsLast() != tt.wantLast {
+				t.Errorf("IsLast() = %v, want %v", hop.IsLast(), tt.wantLast)
+			}
+		})
+	}
+}
@@ -1,7 +1,12 @@
 package repository
 
-import "nyagoPing/internal/domain/model"
+import (
+	"context"
+	"nyagoPing/internal/domain/model"
+)
 
+// PingRepository は ctx がキャンセルされるか期限を迎えると送信を止め、onFinish を呼んでから戻ります。
+// タイムアウトやロス、送信エラー、重複応答は onEvent で通知されます。
 type PingRepository interface {
-	Ping(target *model.PingTarget, config *model.PingConfig, art *model.ASCIIArt, onRecv func(*model.PingPacket), onFinish func(*model.PingStatistics)) error
+	Ping(ctx context.Context, target *model.PingTarget, config *model.PingConfig, art *model.ASCIIArt, onRecv func(*model.PingPacket), onEvent func(*model.PingEvent), onFinish func(*model.PingStatistics)) error
 }
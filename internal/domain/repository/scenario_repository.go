@@ -0,0 +1,7 @@
+package repository
+
+import "nyagoPing/internal/domain/model"
+
+type ScenarioRepository interface {
+	Load(path string) (*model.SimulationScenario, error)
+}
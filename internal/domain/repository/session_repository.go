@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"nyagoPing/internal/domain/model"
+)
+
+// SessionRecorder は記録を順に書き出します。複数のゴルーチンから同時に呼び出せます。
+type SessionRecorder interface {
+	Record(record *model.SessionRecord) error
+	Close() error
+}
+
+// SessionExporter は記録を表計算ソフトなど他のツール向けの形式で書き出します。読み戻しはできません。
+type SessionExporter interface {
+	Create(path string) (SessionRecorder, error)
+}
+
+type SessionRepository interface {
+	Create(path string) (SessionRecorder, error)
+	Load(path string) (*model.Session, error)
+}
@@ -0,0 +1,7 @@
+package repository
+
+import "nyagoPing/internal/domain/model"
+
+type ThresholdRepository interface {
+	Load(path string) (*model.RttThresholds, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"net"
+	"nyagoPing/internal/domain/model"
+)
+
+// TraceRepository は TTL を制限したプローブで経路上のルーターを調べます。
+type TraceRepository interface {
+	// Resolve は target の宛先アドレスを解決して設定します。
+	Resolve(ctx context.Context, target *model.PingTarget, config *model.TraceConfig) error
+	// ProbeHop は TTL を ttl に制限したプローブを1つ送り、応答したルーターまたは宛先を返します。
+	// 応答待ち時間内に応答がなければ Addr が nil の応答を返します。
+	ProbeHop(ctx context.Context, target *model.PingTarget, config *model.TraceConfig, ttl int) (*model.TraceReply, error)
+	// LookupName は addr の逆引き名を返します。引けなければ空文字を返します。
+	LookupName(ctx context.Context, addr net.IP) string
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"fmt"
+	"nyagoPing/internal/domain/model"
+)
+
+// RenderMode は画像をアートの文字に変換する方式です。
+type RenderMode string
+
+const (
+	// RenderModeASCII は1文字に1画素を割り当て、濃さを asciiChars で表します。
+	RenderModeASCII RenderMode = "ascii"
+	// RenderModeBlocks は1文字に上下2画素を割り当て、半ブロック(▀▄)で表します。
+	RenderModeBlocks RenderMode = "blocks"
+	// RenderModeBraille は1文字に横2×縦4画素を割り当て、点字の点で表します。
+	RenderModeBraille RenderMode = "braille"
+)
+
+// pixelGrid は画像をアートの解像度に縮小したものです。lum は画素ごとの明るさ(0〜1)です。
+type pixelGrid struct {
+	width, height int
+	lum           []float64
+	rgb           []model.RGB
+}
+
+func newPixelGrid(width, height int) *pixelGrid {
+	return &pixelGrid{
+		width:  width,
+		height: height,
+		lum:    make([]float64, width*height),
+		rgb:    make([]model.RGB, width*height),
+	}
+}
+
+func (p *pixelGrid) set(x, y int, lum float64, rgb model.RGB) {
+	p.lum[y*p.width+x] = lum
+	p.rgb[y*p.width+x] = rgb
+}
+
+func (p *pixelGrid) at(x, y int) (float64, model.RGB) {
+	return p.lum[y*p.width+x], p.rgb[y*p.width+x]
+}
+
+// artCell は1文字分の描画結果です。hasBg が false の場合は背景色を持ちません。
+type artCell struct {
+	ch    rune
+	fg    model.RGB
+	bg    model.RGB
+	hasBg bool
+}
+
+// renderer は pixelGrid の画素を文字に割り当てます。
+type renderer interface {
+	// cellSize は1文字が受け持つ画素の横と縦の数です。
+	cellSize() (width, height int)
+	// levels は色を使わない場合に描き分けられる明るさの段階数です。ディザリングはこの段階に丸めます。
+	levels() int
+	// render は左上が (x, y) の画素から1文字を描きます。color が false の場合、色は使われません。
+	render(grid *pixelGrid, x, y int, color bool) artCell
+	// usesLuminance は render が明るさで文字を選ぶかどうかです。false の場合、絵は色だけで描かれます。
+	usesLuminance(color bool) bool
+}
+
+func newRenderer(mode RenderMode) (renderer, error) {
+	switch mode {
+	case "", RenderModeASCII:
+		return asciiRenderer{}, nil
+	case RenderModeBlocks:
+		return blockRenderer{}, nil
+	case RenderModeBraille:
+		return brailleRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("未知の描画モードです: %s", mode)
+	}
+}
+
+// litThreshold は blocks と braille で画素を点灯とみなす明るさです。
+const litThreshold = 0.5
+
+type asciiRenderer struct{}
+
+func (asciiRenderer) cellSize() (int, int) { return 1, 1 }
+
+func (asciiRenderer) levels() int { return len(asciiChars) }
+
+func (asciiRenderer) usesLuminance(bool) bool { return true }
+
+func (asciiRenderer) render(grid *pixelGrid, x, y int, _ bool) artCell {
+	lum, rgb := grid.at(x, y)
+	return artCell{ch: RampRune(lum), fg: rgb}
+}
+
+type blockRenderer struct{}
+
+func (blockRenderer) cellSize() (int, int) { return 1, 2 }
+
+func (blockRenderer) levels() int { return 2 }
+
+func (blockRenderer) usesLuminance(color bool) bool { return !color }
+
+// render は色付きなら上の画素を文字色、下の画素を背景色にした ▀ を、色なしなら点灯した画素の組み合わせに応じた半ブロックを描きます。
+func (blockRenderer) render(grid *pixelGrid, x, y int, color bool) artCell {
+	topLum, top := grid.at(x, y)
+	bottomLum, bottom := grid.at(x, y+1)
+	if color {
+		return artCell{ch: '▀', fg: top, bg: bottom, hasBg: true}
+	}
+
+	switch upper, lower := topLum >= litThreshold, bottomLum >= litThreshold; {
+	case upper && lower:
+		return artCell{ch: '█'}
+	case upper:
+		return artCell{ch: '▀'}
+	case lower:
+		return artCell{ch: '▄'}
+	default:
+		return artCell{ch: ' '}
+	}
+}
+
+type brailleRenderer struct{}
+
+func (brailleRenderer) cellSize() (int, int) { return 2, 4 }
+
+func (brailleRenderer) levels() int { return 2 }
+
+func (brailleRenderer) usesLuminance(bool) bool { return true }
+
+// brailleDots は点字の横2×縦4の位置ごとのビットです。U+2800 にこれらを足すと点字になります。
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// render は点灯した画素を点にし、その平均の色を文字色にします。点のない文字はセル全体の平均の色にします。
+func (brailleRenderer) render(grid *pixelGrid, x, y int, _ bool) artCell {
+	ch := rune(0x2800)
+	var lit, all [3]int
+	litCount := 0
+	for dy := 0; dy < 4; dy++ {
+		for dx := 0; dx < 2; dx++ {
+			lum, rgb := grid.at(x+dx, y+dy)
+			addRGB(&all, rgb)
+			if lum >= litThreshold {
+				ch += brailleDots[dy][dx]
+				addRGB(&lit, rgb)
+				litCount++
+			}
+		}
+	}
+	if litCount > 0 {
+		return artCell{ch: ch, fg: averageRGB(lit, litCount)}
+	}
+	return artCell{ch: ch, fg: averageRGB(all, 8)}
+}
+
+func addRGB(sum *[3]int, c model.RGB) {
+	sum[0] += int(c.R)
+	sum[1] += int(c.G)
+	sum[2] += int(c.B)
+}
+
+func averageRGB(sum [3]int, n int) model.RGB {
+	return model.RGB{R: uint8(sum[0] / n), G: uint8(sum[1] / n), B: uint8(sum[2] / n)}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"testing"
+
+	"nyagoPing/internal/domain/model"
+)
+
+// newTestGrid は lit の "#" を明るい画素、それ以外を暗い画素にした pixelGrid を作ります。
+func newTestGrid(lit ...string) *pixelGrid {
+	grid := newPixelGrid(len(lit[0]), len(lit))
+	for y, row := range lit {
+		for x, c := range row {
+			if c == '#' {
+				grid.set(x, y, 1, model.RGB{R: 255})
+			} else {
+				grid.set(x, y, 0, model.RGB{B: 255})
+			}
+		}
+	}
+	return grid
+}
+
+func TestBlockRenderer(t *testing.T) {
+	grid := newTestGrid(
+		"##..",
+		"#.#.",
+	)
+	r := blockRenderer{}
+	var got []rune
+	for x := 0; x < 4; x++ {
+		got = append(got, r.render(grid, x, 0, false).ch)
+	}
+	if string(got) != "█▀▄ " {
+		t.Errorf("render() = %q, want %q", string(got), "█▀▄ ")
+	}
+
+	cell := r.render(grid, 1, 0, true)
+	if cell.ch != '▀' || !cell.hasBg || cell.fg != (model.RGB{R: 255}) || cell.bg != (model.RGB{B: 255}) {
+		t.Errorf("色付きの render() = %+v", cell)
+	}
+}
+
+func TestBrailleRenderer(t *testing.T) {
+	grid := newTestGrid(
+		"#...",
+		".#..",
+		"....",
+		"##..",
+	)
+	r := brailleRenderer{}
+	cell := r.render(grid, 0, 0, true)
+	// 点1・5・7・8
+	if want := rune(0x2800 + 0x01 + 0x10 + 0x40 + 0x80); cell.ch != want {
+		t.Errorf("render() = %U, want %U", cell.ch, want)
+	}
+	if cell.fg != (model.RGB{R: 255}) {
+		t.Errorf("render() の色 = %v, want 点灯した画素の平均", cell.fg)
+	}
+
+	empty := r.render(grid, 2, 0, true)
+	if empty.ch != 0x2800 || empty.fg != (model.RGB{B: 255}) {
+		t.Errorf("点のない render() = %+v", empty)
+	}
+}
+
+func TestNewRenderer_Unknown(t *testing.T) {
+	if _, err := newRenderer("sixel"); err == nil {
+		t.Error("未知のモードでエラーになりませんでした")
+	}
+}
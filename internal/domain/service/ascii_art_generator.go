@@ -8,7 +8,9 @@ import (
 	"nyagoPing/internal/domain/model"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 )
 
 type ASCIIArtGenerator struct{}
@@ -26,12 +28,45 @@ var asciiChars = []rune{
 	'W', '&', '8', '%', 'B', '@', '$',
 }
 
+// RampRune は濃さ level (0〜1) に対応するアート文字を asciiChars から返します。
+func RampRune(level float64) rune {
+	charIndex := int(level * float64(len(asciiChars)-1))
+	if charIndex < 0 {
+		charIndex = 0
+	}
+	if charIndex >= len(asciiChars) {
+		charIndex = len(asciiChars) - 1
+	}
+	return asciiChars[charIndex]
+}
+
 const (
 	maxTerminalWidth  = 200
 	maxTerminalHeight = 60
 )
 
-func (g *ASCIIArtGenerator) GenerateFromImage(imagePath string, width int) (*model.ASCIIArt, error) {
+// GenerateOptions は画像からアートを作る際の設定です。
+type GenerateOptions struct {
+	Width int
+	// Color は各文字の位置の画素の色をアートに残します。
+	Color bool
+	// Mode は画素を文字に変換する方式です。空の場合は RenderModeASCII です。
+	Mode RenderMode
+	// Dither は明るさ(Color の場合は色も)を段階に丸めるときのディザリング方式です。空の場合は DitherNone です。
+	Dither DitherMode
+	// Invert は明るい画素ほど薄い文字にします。明るい背景の端末向けです。色だけで描く blocks の色付きでは色を反転します。
+	Invert bool
+	// Contrast は明るさの中間(0.5)を中心とした倍率です。1で補正なし、0ですべての画素が中間の明るさになります。
+	Contrast float64
+	// Brightness は明るさ(0〜1)に足す量です。
+	Brightness float64
+	// Gamma はガンマ補正の値です。1で補正なし、1より大きいと暗部が明るくなります。0以下は指定できません。
+	Gamma float64
+	// AutoLevels は画像の最も暗い画素と明るい画素が 0〜1 に広がるように明るさを引き伸ばします。
+	AutoLevels bool
+}
+
+func (g *ASCIIArtGenerator) GenerateFromImage(imagePath string, opts GenerateOptions) (*model.ASCIIArt, error) {
 	file, err := os.Open(imagePath)
 	if err != nil {
 		return nil, fmt.Errorf("画像ファイルを開けません: %w", err)
@@ -43,10 +78,10 @@ func (g *ASCIIArtGenerator) GenerateFromImage(imagePath string, width int) (*mod
 		return nil, fmt.Errorf("画像をデコードできません: %w", err)
 	}
 
-	return g.convertImageToASCII(img, width)
+	return g.convertImageToASCII(img, opts)
 }
 
-func (g *ASCIIArtGenerator) GenerateFromImagesInDirectory(dirPath string, width int) ([]*model.ASCIIArt, []string, error) {
+func (g *ASCIIArtGenerator) GenerateFromImagesInDirectory(dirPath string, opts GenerateOptions) ([]*model.ASCIIArt, []string, error) {
 	if _, err := os.Stat(dirPath); os.IsNotExist(err) {
 		return nil, nil, fmt.Errorf("ディレクトリが存在しません: %s", dirPath)
 	}
@@ -72,7 +107,7 @@ func (g *ASCIIArtGenerator) GenerateFromImagesInDirectory(dirPath string, width
 		}
 
 		imagePath := filepath.Join(dirPath, filename)
-		art, err := g.GenerateFromImage(imagePath, width)
+		art, err := g.GenerateFromImage(imagePath, opts)
 		if err != nil {
 			return nil, nil, fmt.Errorf("画像 %s の変換エラー: %w", filename, err)
 		}
@@ -88,11 +123,12 @@ func (g *ASCIIArtGenerator) GenerateFromImagesInDirectory(dirPath string, width
 	return arts, filenames, nil
 }
 
-func (g *ASCIIArtGenerator) convertImageToASCII(img image.Image, width int) (*model.ASCIIArt, error) {
+func (g *ASCIIArtGenerator) convertImageToASCII(img image.Image, opts GenerateOptions) (*model.ASCIIArt, error) {
 	bounds := img.Bounds()
 	imgWidth := bounds.Dx()
 	imgHeight := bounds.Dy()
 
+	width := opts.Width
 	if width <= 0 {
 		width = 80
 	}
@@ -116,29 +152,112 @@ func (g *ASCIIArtGenerator) convertImageToASCII(img image.Image, width int) (*mo
 		height = 1
 	}
 
+	r, err := newRenderer(opts.Mode)
+	if err != nil {
+		return nil, err
+	}
+	cellWidth, cellHeight := r.cellSize()
+	grid := samplePixels(img, width*cellWidth, height*cellHeight)
+	if err := adjustTone(grid, opts); err != nil {
+		return nil, err
+	}
+	// 色だけで描く方式では明るさを反転しても絵が変わらないため、色を反転する
+	if opts.Invert && !r.usesLuminance(opts.Color) {
+		invertColors(grid)
+	}
+	if err := ditherPixels(grid, opts.Dither, r.levels(), opts.Color); err != nil {
+		return nil, err
+	}
+
 	var lines []string
+	var colors, backgrounds [][]model.RGB
 
 	for y := 0; y < height; y++ {
 		var line strings.Builder
+		var lineColors, lineBackgrounds []model.RGB
 		for x := 0; x < width; x++ {
-			imgX := int(float64(x) * float64(imgWidth) / float64(width))
-			imgY := int(float64(y) * float64(imgHeight) / float64(height))
+			cell := r.render(grid, x*cellWidth, y*cellHeight, opts.Color)
+			line.WriteRune(cell.ch)
+			if opts.Color {
+				lineColors = append(lineColors, cell.fg)
+				if cell.hasBg {
+					lineBackgrounds = append(lineBackgrounds, cell.bg)
+				}
+			}
+		}
+		lines = append(lines, line.String())
+		if opts.Color {
+			colors = append(colors, lineColors)
+			if lineBackgrounds != nil {
+				backgrounds = append(backgrounds, lineBackgrounds)
+			}
+		}
+	}
 
-			r, g, b, _ := img.At(imgX, imgY).RGBA()
+	switch {
+	case backgrounds != nil:
+		return model.NewColoredASCIIArtWithBackground(lines, colors, backgrounds)
+	case opts.Color:
+		return model.NewColoredASCIIArt(lines, colors)
+	default:
+		return model.NewASCIIArt(lines)
+	}
+}
 
-			gray := (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)) / 257.0
+// samplePixels は画像を width×height 画素に縮小します。各画素は対応する範囲の元画像の画素の平均(ボックスフィルタ)です。
+// 1点だけを拾うと細い線が消えたり途切れたりするため、範囲内のすべての画素を数えます。行ごとに複数のゴルーチンで並行して計算します。
+func samplePixels(img image.Image, width, height int) *pixelGrid {
+	grid := newPixelGrid(width, height)
 
-			charIndex := int(gray / 255.0 * float64(len(asciiChars)-1))
-			if charIndex >= len(asciiChars) {
-				charIndex = len(asciiChars) - 1
+	workers := min(runtime.GOMAXPROCS(0), height)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(first int) {
+			defer wg.Done()
+			for y := first; y < height; y += workers {
+				for x := 0; x < width; x++ {
+					lum, rgb := averageArea(img, x, y, width, height)
+					grid.set(x, y, lum, rgb)
+				}
 			}
+		}(w)
+	}
+	wg.Wait()
+	return grid
+}
 
-			line.WriteRune(asciiChars[charIndex])
+// averageArea は width×height に縮小したときの (x, y) の画素が覆う元画像の範囲の平均の明るさと色を返します。
+func averageArea(img image.Image, x, y, width, height int) (float64, model.RGB) {
+	bounds := img.Bounds()
+	x0, x1 := areaSpan(bounds.Min.X, bounds.Dx(), x, width)
+	y0, y1 := areaSpan(bounds.Min.Y, bounds.Dy(), y, height)
+
+	var sumR, sumG, sumB uint64
+	for imgY := y0; imgY < y1; imgY++ {
+		for imgX := x0; imgX < x1; imgX++ {
+			r, g, b, _ := img.At(imgX, imgY).RGBA()
+			sumR += uint64(r)
+			sumG += uint64(g)
+			sumB += uint64(b)
 		}
-		lines = append(lines, line.String())
 	}
+	n := uint64((x1 - x0) * (y1 - y0))
+	r, g, b := sumR/n, sumG/n, sumB/n
+
+	gray := (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)) / 257.0
 
-	return model.NewASCIIArt(lines)
+	return gray / 255.0, model.RGB{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8)}
+}
+
+// areaSpan は長さ size の元画像を count 個に分けたうちの i 番目の範囲 [from, to) を返します。縮小でない場合も1画素は含めます。
+func areaSpan(origin, size, i, count int) (int, int) {
+	from := origin + i*size/count
+	to := origin + (i+1)*size/count
+	if to <= from {
+		to = from + 1
+	}
+	return from, to
 }
 
 func (g *ASCIIArtGenerator) CalculateOptimalCount(art *model.ASCIIArt) int {
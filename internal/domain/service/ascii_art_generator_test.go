@@ -4,9 +4,12 @@ import (
 	"image"
 	"image/color"
 	"image/png"
+	"math"
 	"os"
 	"path/filepath"
 	"testing"
+
+	"nyagoPing/internal/domain/model"
 )
 
 func TestASCIIArtGenerator_GenerateFromImage(t *testing.T) {
@@ -33,7 +36,7 @@ func TestASCIIArtGenerator_GenerateFromImage(t *testing.T) {
 	}
 	f.Close()
 
-	art, err := generator.GenerateFromImage(testImagePath, 40)
+	art, err := generator.GenerateFromImage(testImagePath, GenerateOptions{Width: 40, Contrast: 1, Gamma: 1})
 	if err != nil {
 		t.Errorf("GenerateFromImage() error = %v", err)
 		return
@@ -67,7 +70,7 @@ func TestASCIIArtGenerator_GenerateFromImagesInDirectory(t *testing.T) {
 		f.Close()
 	}
 
-	arts, filenames, err := generator.GenerateFromImagesInDirectory(tmpDir, 40)
+	arts, filenames, err := generator.GenerateFromImagesInDirectory(tmpDir, GenerateOptions{Width: 40, Contrast: 1, Gamma: 1})
 	if err != nil {
 		t.Errorf("GenerateFromImagesInDirectory() error = %v", err)
 		return
@@ -93,10 +96,202 @@ func TestASCIIArtGenerator_CalculateOptimalCount(t *testing.T) {
 	png.Encode(f, img)
 	f.Close()
 
-	art, _ := generator.GenerateFromImage(testImagePath, 40)
+	art, _ := generator.GenerateFromImage(testImagePath, GenerateOptions{Width: 40, Contrast: 1, Gamma: 1})
 
 	count := generator.CalculateOptimalCount(art)
 	if count != art.LineCount() {
 		t.Errorf("CalculateOptimalCount() = %v, want %v", count, art.LineCount())
 	}
 }
+
+func TestASCIIArtGenerator_GenerateFromImage_Color(t *testing.T) {
+	generator := NewASCIIArtGenerator()
+
+	tmpDir := t.TempDir()
+	testImagePath := filepath.Join(tmpDir, "test.png")
+
+	// 左半分が赤、右半分が青の画像
+	img := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 100; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= 50 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	f, _ := os.Create(testImagePath)
+	png.Encode(f, img)
+	f.Close()
+
+	plain, err := generator.GenerateFromImage(testImagePath, GenerateOptions{Width: 10, Contrast: 1, Gamma: 1})
+	if err != nil {
+		t.Fatalf("GenerateFromImage() error = %v", err)
+	}
+	if plain.HasColor() {
+		t.Error("Color を指定していないアートに色があります")
+	}
+
+	art, err := generator.GenerateFromImage(testImagePath, GenerateOptions{Width: 10, Contrast: 1, Gamma: 1, Color: true})
+	if err != nil {
+		t.Fatalf("GenerateFromImage() error = %v", err)
+	}
+	if !art.HasColor() {
+		t.Fatal("Color を指定したアートに色がありません")
+	}
+	colors := art.GetColors(0)
+	if len(colors) != 10 {
+		t.Fatalf("1行目の色の数 = %d, want 10", len(colors))
+	}
+	if colors[0] != (model.RGB{R: 255}) || colors[9] != (model.RGB{B: 255}) {
+		t.Errorf("1行目の色 = %v", colors)
+	}
+	if art.GetLine(0) != plain.GetLine(0) {
+		t.Errorf("色の有無で文字が変わりました: %q, %q", art.GetLine(0), plain.GetLine(0))
+	}
+}
+
+func TestASCIIArtGenerator_GenerateFromImage_Modes(t *testing.T) {
+	generator := NewASCIIArtGenerator()
+
+	tmpDir := t.TempDir()
+	testImagePath := filepath.Join(tmpDir, "test.png")
+	img := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 100; x++ {
+			img.Set(x, y, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+		}
+	}
+	f, _ := os.Create(testImagePath)
+	png.Encode(f, img)
+	f.Close()
+
+	tests := []struct {
+		mode           RenderMode
+		color          bool
+		wantRune       rune
+		wantBackground bool
+	}{
+		{RenderModeASCII, false, '@', false},
+		{RenderModeBlocks, false, '█', false},
+		{RenderModeBlocks, true, '▀', true},
+		{RenderModeBraille, false, '⣿', false},
+	}
+	for _, tt := range tests {
+		art, err := generator.GenerateFromImage(testImagePath, GenerateOptions{Width: 10, Contrast: 1, Gamma: 1, Mode: tt.mode, Color: tt.color})
+		if err != nil {
+			t.Fatalf("GenerateFromImage(%s) error = %v", tt.mode, err)
+		}
+		if got := []rune(art.GetLine(0)); len(got) != 10 || got[0] != tt.wantRune {
+			t.Errorf("GenerateFromImage(%s, color=%v) 1行目 = %q", tt.mode, tt.color, art.GetLine(0))
+		}
+		if art.HasBackground() != tt.wantBackground {
+			t.Errorf("GenerateFromImage(%s, color=%v) HasBackground() = %v", tt.mode, tt.color, art.HasBackground())
+		}
+	}
+
+	if _, err := generator.GenerateFromImage(testImagePath, GenerateOptions{Mode: "sixel"}); err == nil {
+		t.Error("未知のモードでエラーになりませんでした")
+	}
+}
+
+func TestASCIIArtGenerator_Invert_Modes(t *testing.T) {
+	generator := NewASCIIArtGenerator()
+	img := image.NewRGBA(image.Rect(0, 0, 40, 40))
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 40; x++ {
+			img.Set(x, y, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+		}
+	}
+
+	white, black := model.RGB{R: 255, G: 255, B: 255}, model.RGB{}
+	tests := []struct {
+		mode     RenderMode
+		color    bool
+		wantRune rune
+		wantFg   model.RGB
+	}{
+		{RenderModeASCII, false, ' ', model.RGB{}},
+		// 明るさで文字を選ぶ方式では文字だけを反転し、色は画像のまま
+		{RenderModeASCII, true, ' ', white},
+		{RenderModeBlocks, false, ' ', model.RGB{}},
+		// 色だけで描く方式では色を反転する
+		{RenderModeBlocks, true, '▀', black},
+		{RenderModeBraille, false, '⠀', model.RGB{}},
+		{RenderModeBraille, true, '⠀', white},
+	}
+	for _, tt := range tests {
+		art, err := generator.convertImageToASCII(img, GenerateOptions{Width: 10, Contrast: 1, Gamma: 1, Mode: tt.mode, Color: tt.color, Invert: true})
+		if err != nil {
+			t.Fatalf("convertImageToASCII(%s, color=%v) error = %v", tt.mode, tt.color, err)
+		}
+		if got := []rune(art.GetLine(0)); got[0] != tt.wantRune {
+			t.Errorf("convertImageToASCII(%s, color=%v) 1行目 = %q, want %q", tt.mode, tt.color, art.GetLine(0), tt.wantRune)
+		}
+		if !tt.color {
+			continue
+		}
+		if got := art.GetColors(0)[0]; got != tt.wantFg {
+			t.Errorf("convertImageToASCII(%s, color=%v) 文字色 = %v, want %v", tt.mode, tt.color, got, tt.wantFg)
+		}
+		if art.HasBackground() {
+			if got := art.GetBackgrounds(0)[0]; got != tt.wantFg {
+				t.Errorf("convertImageToASCII(%s, color=%v) 背景色 = %v, want %v", tt.mode, tt.color, got, tt.wantFg)
+			}
+		}
+	}
+}
+
+func TestSamplePixels_ThinLine(t *testing.T) {
+	// 黒地に幅1画素の白い縦線。1点だけを拾うと x=0,10,20,... の画素しか見ないため線が消える
+	img := image.NewRGBA(image.Rect(0, 0, 100, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 100; x++ {
+			img.Set(x, y, color.RGBA{A: 255})
+		}
+		img.Set(55, y, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+	}
+
+	grid := samplePixels(img, 10, 2)
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 10; x++ {
+			lum, _ := grid.at(x, y)
+			if want := map[bool]float64{true: 0.1, false: 0}[x == 5]; math.Abs(lum-want) > 0.01 {
+				t.Errorf("(%d, %d) の明るさ = %v, want %v", x, y, lum, want)
+			}
+		}
+	}
+}
+
+func TestSamplePixels_Upscale(t *testing.T) {
+	img := image.NewRGBA(image.Rect(10, 10, 12, 11))
+	img.Set(11, 10, color.RGBA{R: 255, A: 255})
+
+	grid := samplePixels(img, 4, 2)
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 4; x++ {
+			_, rgb := grid.at(x, y)
+			if want := (model.RGB{R: map[bool]uint8{true: 255}[x >= 2]}); rgb != want {
+				t.Errorf("(%d, %d) の色 = %v, want %v", x, y, rgb, want)
+			}
+		}
+	}
+}
+
+func BenchmarkASCIIArtGenerator_ConvertImageToASCII(b *testing.B) {
+	img := image.NewRGBA(image.Rect(0, 0, 3000, 2000))
+	for y := 0; y < 2000; y++ {
+		for x := 0; x < 3000; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: uint8(x ^ y), A: 255})
+		}
+	}
+	generator := NewASCIIArtGenerator()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := generator.convertImageToASCII(img, GenerateOptions{Width: 80, Contrast: 1, Gamma: 1, Color: true}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
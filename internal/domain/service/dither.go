@@ -0,0 +1,118 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"nyagoPing/internal/domain/model"
+)
+
+// DitherMode は画素を文字や色の段階に丸めるときのディザリング方式です。
+type DitherMode string
+
+const (
+	DitherNone DitherMode = "none"
+	// DitherFloydSteinberg は丸めた誤差を右と下の4画素に 7:3:5:1 で拡散します。
+	DitherFloydSteinberg DitherMode = "floyd-steinberg"
+	// DitherAtkinson は誤差の 3/4 だけを周囲の6画素に拡散します。明暗のはっきりした絵になります。
+	DitherAtkinson DitherMode = "atkinson"
+	// DitherOrdered は4×4のBayer行列で閾値をずらします。誤差を拡散しないため模様が規則的になります。
+	DitherOrdered DitherMode = "ordered"
+)
+
+// diffusionWeight は誤差を拡散する先の相対位置と割合です。
+type diffusionWeight struct {
+	dx, dy int
+	weight float64
+}
+
+var diffusionKernels = map[DitherMode][]diffusionWeight{
+	DitherFloydSteinberg: {
+		{1, 0, 7.0 / 16}, {-1, 1, 3.0 / 16}, {0, 1, 5.0 / 16}, {1, 1, 1.0 / 16},
+	},
+	DitherAtkinson: {
+		{1, 0, 1.0 / 8}, {2, 0, 1.0 / 8}, {-1, 1, 1.0 / 8}, {0, 1, 1.0 / 8}, {1, 1, 1.0 / 8}, {0, 2, 1.0 / 8},
+	},
+}
+
+var bayer4x4 = [4][4]float64{
+	{0, 8, 2, 10},
+	{12, 4, 14, 6},
+	{3, 11, 1, 9},
+	{15, 7, 13, 5},
+}
+
+// paletteLevels は色をディザリングするときの各チャンネルの段階です。256色パレットの色立方体と同じ値にし、256色の端末でも模様が崩れないようにします。
+var paletteLevels = []float64{0, 95, 135, 175, 215, 255}
+
+// ditherPixels は grid の明るさを levels 段階に丸め、color が true なら色も paletteLevels に丸めます。
+func ditherPixels(grid *pixelGrid, mode DitherMode, levels int, color bool) error {
+	if mode == "" || mode == DitherNone {
+		return nil
+	}
+	if _, ok := diffusionKernels[mode]; !ok && mode != DitherOrdered {
+		return fmt.Errorf("未知のディザリング方式です: %s", mode)
+	}
+
+	step := 1 / float64(levels-1)
+	ditherChannel(grid.lum, grid.width, grid.height, mode, step, func(v float64) float64 {
+		return math.Max(0, math.Min(1, math.Round(v/step)*step))
+	})
+
+	if !color {
+		return nil
+	}
+	channels := [3][]float64{}
+	for c := range channels {
+		channels[c] = make([]float64, len(grid.rgb))
+	}
+	for i, rgb := range grid.rgb {
+		channels[0][i], channels[1][i], channels[2][i] = float64(rgb.R), float64(rgb.G), float64(rgb.B)
+	}
+	for _, channel := range channels {
+		ditherChannel(channel, grid.width, grid.height, mode, 255.0/float64(len(paletteLevels)-1), nearestPaletteLevel)
+	}
+	for i := range grid.rgb {
+		grid.rgb[i] = model.RGB{R: uint8(channels[0][i]), G: uint8(channels[1][i]), B: uint8(channels[2][i])}
+	}
+	return nil
+}
+
+// ditherChannel は1チャンネル分の値を quantize で丸めます。step は ordered で閾値をずらす幅です。
+func ditherChannel(values []float64, width, height int, mode DitherMode, step float64, quantize func(float64) float64) {
+	if mode == DitherOrdered {
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				offset := ((bayer4x4[y%4][x%4]+0.5)/16 - 0.5) * step
+				values[y*width+x] = quantize(values[y*width+x] + offset)
+			}
+		}
+		return
+	}
+
+	kernel := diffusionKernels[mode]
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			i := y*width + x
+			old := values[i]
+			values[i] = quantize(old)
+			diff := old - values[i]
+			for _, w := range kernel {
+				nx, ny := x+w.dx, y+w.dy
+				if nx < 0 || nx >= width || ny >= height {
+					continue
+				}
+				values[ny*width+nx] += diff * w.weight
+			}
+		}
+	}
+}
+
+func nearestPaletteLevel(v float64) float64 {
+	nearest := paletteLevels[0]
+	for _, level := range paletteLevels[1:] {
+		if math.Abs(v-level) < math.Abs(v-nearest) {
+			nearest = level
+		}
+	}
+	return nearest
+}
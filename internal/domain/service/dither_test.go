@@ -0,0 +1,87 @@
+package service
+
+import (
+	"testing"
+
+	"nyagoPing/internal/domain/model"
+)
+
+// newGradientGrid は左から右へ明るさが 0 から 1 に変わる pixelGrid を作ります。
+func newGradientGrid(width, height int) *pixelGrid {
+	grid := newPixelGrid(width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			v := float64(x) / float64(width-1)
+			grid.set(x, y, v, model.RGB{R: uint8(v * 255), G: 100, B: 30})
+		}
+	}
+	return grid
+}
+
+func TestDitherPixels(t *testing.T) {
+	for _, mode := range []DitherMode{DitherFloydSteinberg, DitherAtkinson, DitherOrdered} {
+		t.Run(string(mode), func(t *testing.T) {
+			grid := newGradientGrid(32, 8)
+			if err := ditherPixels(grid, mode, 2, true); err != nil {
+				t.Fatalf("ditherPixels() error = %v", err)
+			}
+
+			lit := 0
+			for _, lum := range grid.lum {
+				if lum != 0 && lum != 1 {
+					t.Fatalf("2段階に丸められていない明るさ %v があります", lum)
+				}
+				if lum == 1 {
+					lit++
+				}
+			}
+			// 明るさの平均がおよそ保たれる
+			if ratio := float64(lit) / float64(len(grid.lum)); ratio < 0.35 || ratio > 0.65 {
+				t.Errorf("点灯した画素の割合 = %.2f, want 約0.5", ratio)
+			}
+
+			for _, rgb := range grid.rgb {
+				for _, v := range []uint8{rgb.R, rgb.G, rgb.B} {
+					if nearestPaletteLevel(float64(v)) != float64(v) {
+						t.Fatalf("パレットに丸められていない色 %v があります", rgb)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDitherPixels_None(t *testing.T) {
+	grid := newGradientGrid(8, 2)
+	want := append([]float64(nil), grid.lum...)
+	if err := ditherPixels(grid, DitherNone, 2, false); err != nil {
+		t.Fatalf("ditherPixels() error = %v", err)
+	}
+	for i := range want {
+		if grid.lum[i] != want[i] {
+			t.Fatalf("DitherNone で明るさが変わりました: %v", grid.lum)
+		}
+	}
+
+	if err := ditherPixels(grid, "jarvis", 2, false); err == nil {
+		t.Error("未知の方式でエラーになりませんでした")
+	}
+}
+
+func TestDitherPixels_ASCIILevelsMapToOwnRune(t *testing.T) {
+	// 段階に丸めた明るさは RampRune でその段階の文字になり、1つ薄い文字にずれない
+	levels := len(asciiChars)
+	grid := newPixelGrid(levels, 1)
+	for k := 0; k < levels; k++ {
+		grid.set(k, 0, float64(k)/float64(levels-1), model.RGB{})
+	}
+	if err := ditherPixels(grid, DitherOrdered, levels, false); err != nil {
+		t.Fatalf("ditherPixels() error = %v", err)
+	}
+	for k := 0; k < levels; k++ {
+		lum, _ := grid.at(k, 0)
+		if got := RampRune(lum); got != asciiChars[k] {
+			t.Errorf("段階 %d の文字 = %q, want %q", k, got, asciiChars[k])
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package service
+
+import "strings"
+
+// HistogramBar は count を maxCount に対する割合で最大 width 桁の棒にして返します。
+// 満たされた桁は最も濃いアート文字で埋め、端数の桁は asciiChars の濃淡で描きます。
+func HistogramBar(count, maxCount, width int) string {
+	if count <= 0 || maxCount <= 0 || width <= 0 {
+		return ""
+	}
+	if count > maxCount {
+		count = maxCount
+	}
+
+	length := float64(count) / float64(maxCount) * float64(width)
+	full := int(length)
+
+	var bar strings.Builder
+	bar.WriteString(strings.Repeat(string(RampRune(1)), full))
+	if fraction := length - float64(full); fraction > 0 {
+		if r := RampRune(fraction); r != ' ' {
+			bar.WriteRune(r)
+		}
+	}
+	// ごく少ない件数でも区間に値があることが分かるよう1文字は描く
+	if bar.Len() == 0 {
+		bar.WriteRune(RampRune(1.0 / float64(len(asciiChars)-1)))
+	}
+	return bar.String()
+}
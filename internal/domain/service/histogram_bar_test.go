@@ -0,0 +1,29 @@
+package service
+
+import "testing"
+
+func TestHistogramBar(t *testing.T) {
+	full := string(RampRune(1))
+
+	tests := []struct {
+		name     string
+		count    int
+		maxCount int
+		width    int
+		want     string
+	}{
+		{name: "最大", count: 10, maxCount: 10, width: 4, want: full + full + full + full},
+		{name: "半分", count: 5, maxCount: 10, width: 4, want: full + full},
+		{name: "端数", count: 3, maxCount: 4, width: 2, want: full + string(RampRune(0.5))},
+		{name: "ごく少ない", count: 1, maxCount: 1000, width: 10, want: "."},
+		{name: "ゼロ", count: 0, maxCount: 10, width: 4, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HistogramBar(tt.count, tt.maxCount, tt.width); got != tt.want {
+				t.Errorf("HistogramBar() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
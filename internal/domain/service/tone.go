@@ -0,0 +1,67 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"nyagoPing/internal/domain/model"
+	"sort"
+)
+
+// autoLevelsClip は自動レベル補正で黒と白に切り詰める画素の割合です。わずかな外れ値で補正が効かなくなるのを防ぎます。
+const autoLevelsClip = 0.01
+
+// adjustTone は opts の自動レベル補正・明るさ・コントラスト・ガンマを grid の明るさと色に適用し、Invert なら明るさを反転します。
+// 反転は文字の濃さだけに効かせ、色は元の画像のままにします。
+func adjustTone(grid *pixelGrid, opts GenerateOptions) error {
+	if opts.Contrast < 0 {
+		return fmt.Errorf("コントラストには0以上を指定してください: %v", opts.Contrast)
+	}
+	if opts.Gamma <= 0 {
+		return fmt.Errorf("ガンマには0より大きい値を指定してください: %v", opts.Gamma)
+	}
+
+	low, high := 0.0, 1.0
+	if opts.AutoLevels {
+		low, high = lumRange(grid.lum)
+	}
+
+	curve := func(v float64) float64 {
+		v = (v - low) / (high - low)
+		v += opts.Brightness
+		v = (v-0.5)*opts.Contrast + 0.5
+		v = math.Max(0, math.Min(1, v))
+		return math.Pow(v, 1/opts.Gamma)
+	}
+	for i, lum := range grid.lum {
+		grid.lum[i] = curve(lum)
+		if opts.Invert {
+			grid.lum[i] = 1 - grid.lum[i]
+		}
+		rgb := grid.rgb[i]
+		grid.rgb[i] = model.RGB{
+			R: uint8(math.Round(curve(float64(rgb.R)/255) * 255)),
+			G: uint8(math.Round(curve(float64(rgb.G)/255) * 255)),
+			B: uint8(math.Round(curve(float64(rgb.B)/255) * 255)),
+		}
+	}
+	return nil
+}
+
+// invertColors は grid の色を反転します。
+func invertColors(grid *pixelGrid) {
+	for i, rgb := range grid.rgb {
+		grid.rgb[i] = model.RGB{R: 255 - rgb.R, G: 255 - rgb.G, B: 255 - rgb.B}
+	}
+}
+
+// lumRange は明るさの下位と上位 autoLevelsClip を除いた範囲を返します。ほぼ一色の画像では補正しません。
+func lumRange(lum []float64) (float64, float64) {
+	sorted := append([]float64(nil), lum...)
+	sort.Float64s(sorted)
+	clip := int(float64(len(sorted)) * autoLevelsClip)
+	low, high := sorted[clip], sorted[len(sorted)-1-clip]
+	if high-low < 1.0/255 {
+		return 0, 1
+	}
+	return low, high
+}
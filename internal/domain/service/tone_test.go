@@ -0,0 +1,65 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"nyagoPing/internal/domain/model"
+)
+
+func TestAdjustTone(t *testing.T) {
+	tests := []struct {
+		name string
+		opts GenerateOptions
+		in   float64
+		want float64
+	}{
+		{"補正なし", GenerateOptions{Contrast: 1, Gamma: 1}, 0.3, 0.3},
+		{"反転", GenerateOptions{Contrast: 1, Gamma: 1, Invert: true}, 0.3, 0.7},
+		{"明るさ", GenerateOptions{Contrast: 1, Gamma: 1, Brightness: 0.2}, 0.3, 0.5},
+		{"コントラスト", GenerateOptions{Contrast: 2, Gamma: 1}, 0.3, 0.1},
+		{"コントラストで切り詰め", GenerateOptions{Contrast: 4, Gamma: 1}, 0.3, 0},
+		{"コントラスト0は中間の明るさ", GenerateOptions{Contrast: 0, Gamma: 1}, 0.9, 0.5},
+		{"ガンマ", GenerateOptions{Contrast: 1, Gamma: 2}, 0.25, 0.5},
+		{"補正してから反転", GenerateOptions{Contrast: 1, Gamma: 1, Brightness: 0.2, Invert: true}, 0.3, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grid := newPixelGrid(1, 1)
+			grid.set(0, 0, tt.in, model.RGB{})
+			if err := adjustTone(grid, tt.opts); err != nil {
+				t.Fatalf("adjustTone() error = %v", err)
+			}
+			if got, _ := grid.at(0, 0); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("adjustTone() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdjustTone_AutoLevels(t *testing.T) {
+	grid := newPixelGrid(3, 1)
+	grid.set(0, 0, 0.4, model.RGB{R: 102})
+	grid.set(1, 0, 0.5, model.RGB{R: 127})
+	grid.set(2, 0, 0.6, model.RGB{R: 153})
+	if err := adjustTone(grid, GenerateOptions{Contrast: 1, Gamma: 1, AutoLevels: true, Invert: true}); err != nil {
+		t.Fatalf("adjustTone() error = %v", err)
+	}
+	for i, want := range []float64{1, 0.5, 0} {
+		if math.Abs(grid.lum[i]-want) > 1e-9 {
+			t.Errorf("lum[%d] = %v, want %v", i, grid.lum[i], want)
+		}
+	}
+	// 色も同じ範囲で引き伸ばされ、反転はされない
+	if grid.rgb[0].R != 0 || grid.rgb[2].R != 255 {
+		t.Errorf("rgb = %v", grid.rgb)
+	}
+}
+
+func TestAdjustTone_Invalid(t *testing.T) {
+	for _, opts := range []GenerateOptions{{Contrast: -1, Gamma: 1}, {Contrast: 1, Gamma: 0}, {Contrast: 1, Gamma: -0.5}} {
+		if err := adjustTone(newPixelGrid(1, 1), opts); err == nil {
+			t.Errorf("adjustTone(%+v) でエラーになりませんでした", opts)
+		}
+	}
+}
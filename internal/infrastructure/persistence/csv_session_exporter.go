@@ -0,0 +1,161 @@
+package persistence
+
+import (
+	"encoding/csv"
+	"fmt"
+	"nyagoPing/internal/domain/model"
+	"nyagoPing/internal/domain/repository"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var csvPacketHeader = []string{
+	"time", "host", "type", "seq", "bytes", "ip", "ttl", "rtt_ms", "status", "level", "art_line", "error", "art_colors", "art_backgrounds",
+}
+
+var csvSummaryHeader = []string{
+	"time", "host", "addr", "sent", "recv", "duplicates", "loss_percent",
+	"min_ms", "avg_ms", "max_ms", "stddev_ms", "p50_ms", "p90_ms", "p95_ms", "p99_ms", "jitter_ms",
+}
+
+// CSVSessionExporter はパケットとイベントを1行ずつ CSV に書き出します。
+// 統計は out.csv に対して out.summary.csv のような別ファイルに書きます。
+type CSVSessionExporter struct{}
+
+func NewCSVSessionExporter() repository.SessionExporter {
+	return &CSVSessionExporter{}
+}
+
+// csvSummaryPath は path に対応する統計ファイルのパスを返します。
+func csvSummaryPath(path string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + ".summary" + ext
+}
+
+func (e *CSVSessionExporter) Create(path string) (repository.SessionRecorder, error) {
+	packetFile, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("ファイルを作成できません: %w", err)
+	}
+	summaryFile, err := os.Create(csvSummaryPath(path))
+	if err != nil {
+		packetFile.Close()
+		return nil, fmt.Errorf("ファイルを作成できません: %w", err)
+	}
+
+	r := &csvSessionRecorder{
+		packetFile:    packetFile,
+		packetWriter:  csv.NewWriter(packetFile),
+		summaryFile:   summaryFile,
+		summaryWriter: csv.NewWriter(summaryFile),
+	}
+	if err := r.write(r.packetWriter, csvPacketHeader); err != nil {
+		r.Close()
+		return nil, err
+	}
+	if err := r.write(r.summaryWriter, csvSummaryHeader); err != nil {
+		r.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+type csvSessionRecorder struct {
+	mu            sync.Mutex
+	packetFile    *os.File
+	packetWriter  *csv.Writer
+	summaryFile   *os.File
+	summaryWriter *csv.Writer
+}
+
+func (r *csvSessionRecorder) Record(record *model.SessionRecord) error {
+	timestamp := record.Time.Format(time.RFC3339Nano)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if p := record.Packet; p != nil {
+		var ip, level string
+		if p.IPAddr != nil {
+			ip = p.IPAddr.String()
+		}
+		if p.Level != model.LatencyUnknown {
+			level = p.Level.String()
+		}
+		var status string
+		if p.StatusCode != 0 {
+			status = strconv.Itoa(p.StatusCode)
+		}
+		if err := r.write(r.packetWriter, []string{
+			timestamp, record.Host, "packet", strconv.Itoa(p.Seq), strconv.Itoa(p.Nbytes), ip, strconv.Itoa(p.TTL),
+			csvMillis(p.Rtt), status, level, p.ArtLine, "", csvColors(p.ArtColors), csvColors(p.ArtBackgrounds),
+		}); err != nil {
+			return err
+		}
+	}
+	if e := record.Event; e != nil {
+		var rtt, status, errText string
+		if e.Rtt > 0 {
+			rtt = csvMillis(e.Rtt)
+		}
+		if e.StatusCode != 0 {
+			status = strconv.Itoa(e.StatusCode)
+		}
+		if e.Err != nil {
+			errText = e.Err.Error()
+		}
+		if err := r.write(r.packetWriter, []string{
+			timestamp, record.Host, e.Type.String(), strconv.Itoa(e.Seq), "", "", "",
+			rtt, status, "", e.ArtLine, errText, csvColors(e.ArtColors), csvColors(e.ArtBackgrounds),
+		}); err != nil {
+			return err
+		}
+	}
+	if s := record.Statistics; s != nil {
+		if err := r.write(r.summaryWriter, []string{
+			timestamp, record.Host, s.Addr,
+			strconv.Itoa(s.PacketsSent), strconv.Itoa(s.PacketsRecv), strconv.Itoa(s.PacketsDuplicates),
+			strconv.FormatFloat(s.PacketLoss, 'f', 1, 64),
+			csvMillis(s.MinRtt), csvMillis(s.AvgRtt), csvMillis(s.MaxRtt), csvMillis(s.StdDevRtt),
+			csvMillis(s.P50Rtt), csvMillis(s.P90Rtt), csvMillis(s.P95Rtt), csvMillis(s.P99Rtt), csvMillis(s.Jitter),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// write は長時間の実行でもメモリに溜めないよう1行ごとにファイルへ書き出します。
+func (r *csvSessionRecorder) write(writer *csv.Writer, row []string) error {
+	writer.Write(row)
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("ファイル書き込みエラー: %w", err)
+	}
+	return nil
+}
+
+func (r *csvSessionRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	packetErr := r.packetFile.Close()
+	summaryErr := r.summaryFile.Close()
+	if packetErr != nil {
+		return packetErr
+	}
+	return summaryErr
+}
+
+// csvMillis は表計算ソフトで扱いやすいよう時間をミリ秒の小数で表します。
+func csvMillis(d time.Duration) string {
+	return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 3, 64)
+}
+
+// csvColors はアート行の文字ごとの色を "#rrggbb" を空白で区切って並べます。色のないアートでは空にします。
+func csvColors(colors []model.RGB) string {
+	return strings.Join(formatHexColors(colors), " ")
+}
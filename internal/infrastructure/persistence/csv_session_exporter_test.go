@@ -0,0 +1,101 @@
+package persistence
+
+import (
+	"encoding/csv"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nyagoPing/internal/domain/model"
+)
+
+func readCSV(t *testing.T, path string) [][]string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	return rows
+}
+
+func TestCSVSessionExporter_Create(t *testing.T) {
+	exporter := NewCSVSessionExporter()
+
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "out.csv")
+
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	packet := model.NewPingPacket(0, 64, 57, net.ParseIP("192.0.2.1"), 12500*time.Microsecond, "a,\"b\"")
+	packet.Level = model.LatencyGood
+	packet.ArtColors = []model.RGB{{R: 255}, {G: 128, B: 1}}
+	event := model.NewPingEvent(model.PingEventLost, 1, "line2", errors.New("ステータスコード 503"))
+	event.StatusCode = 503
+	stats := model.CalculatePingStatistics("example.tld", 2, []time.Duration{12500 * time.Microsecond})
+
+	recorder, err := exporter.Create(testFile)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	// 統計を書く前でもパケットの行はファイルに出ている
+	if err := recorder.Record(&model.SessionRecord{Time: start, Host: "example.tld", Packet: packet}); err != nil {
+		t.Errorf("Record() error = %v", err)
+	}
+	if rows := readCSV(t, testFile); len(rows) != 2 {
+		t.Errorf("書き込み途中の行数 = %d, want 2", len(rows))
+	}
+	if err := recorder.Record(&model.SessionRecord{Time: start, Host: "example.tld", Event: event}); err != nil {
+		t.Errorf("Record() error = %v", err)
+	}
+	if err := recorder.Record(&model.SessionRecord{Time: start, Host: "example.tld", Statistics: stats}); err != nil {
+		t.Errorf("Record() error = %v", err)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+
+	rows := readCSV(t, testFile)
+	want := [][]string{
+		csvPacketHeader,
+		{"2024-01-02T03:04:05Z", "example.tld", "packet", "0", "64", "192.0.2.1", "57", "12.500", "", "good", "a,\"b\"", "", "#ff0000 #008001", ""},
+		{"2024-01-02T03:04:05Z", "example.tld", "lost", "1", "", "", "", "", "503", "", "line2", "ステータスコード 503", "", ""},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("行数 = %d, want %d: %v", len(rows), len(want), rows)
+	}
+	for i := range want {
+		for j := range want[i] {
+			if rows[i][j] != want[i][j] {
+				t.Errorf("rows[%d][%d] = %q, want %q", i, j, rows[i][j], want[i][j])
+			}
+		}
+	}
+
+	summary := readCSV(t, filepath.Join(tmpDir, "out.summary.csv"))
+	if len(summary) != 2 || len(summary[1]) != len(csvSummaryHeader) {
+		t.Fatalf("summary = %v", summary)
+	}
+	if summary[1][3] != "2" || summary[1][4] != "1" || summary[1][6] != "50.0" || summary[1][8] != "12.500" {
+		t.Errorf("summary[1] = %v", summary[1])
+	}
+}
+
+func TestCSVSummaryPath(t *testing.T) {
+	tests := map[string]string{
+		"out.csv":       "out.summary.csv",
+		"dir/run.1.csv": "dir/run.1.summary.csv",
+		"noext":         "noext.summary",
+	}
+	for path, want := range tests {
+		if got := csvSummaryPath(path); got != want {
+			t.Errorf("csvSummaryPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -6,8 +6,19 @@ import (
 	"nyagoPing/internal/domain/model"
 	"nyagoPing/internal/domain/repository"
 	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 )
 
+// uncoloredCell は色付きのアートファイルで色の指定がない文字に使う色です。端末の既定の文字色に近い灰色にします。
+var uncoloredCell = model.RGB{R: 0xc0, G: 0xc0, B: 0xc0}
+
+// uncoloredBackground は背景色付きのアートファイルで背景色の指定がない文字に使う色です。
+var uncoloredBackground = model.RGB{}
+
+// FileASCIIArtRepository はアートを1行1行のテキストファイルとして読み書きします。
+// 色付きのアートは文字の色を ANSI の truecolor 指定(ESC[38;2;R;G;Bm、背景色は ESC[48;2;R;G;Bm)で埋め込むため、cat でもそのまま色付きで表示できます。
 type FileASCIIArtRepository struct{}
 
 func NewFileASCIIArtRepository() repository.ASCIIArtRepository {
@@ -22,16 +33,30 @@ func (r *FileASCIIArtRepository) Load(path string) (*model.ASCIIArt, error) {
 	defer file.Close()
 
 	var lines []string
+	var colors, backgrounds [][]model.RGB
+	hasColor, hasBackground := false, false
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
+		parsed := parseColoredLine(scanner.Text())
+		lines = append(lines, parsed.text)
+		colors = append(colors, parsed.colors)
+		backgrounds = append(backgrounds, parsed.backgrounds)
+		hasColor = hasColor || parsed.colored
+		hasBackground = hasBackground || parsed.backgrounded
 	}
 
 	if err := scanner.Err(); err != nil {
 		return nil, fmt.Errorf("ファイル読み込みエラー: %w", err)
 	}
 
-	return model.NewASCIIArt(lines)
+	switch {
+	case hasBackground:
+		return model.NewColoredASCIIArtWithBackground(lines, colors, backgrounds)
+	case hasColor:
+		return model.NewColoredASCIIArt(lines, colors)
+	default:
+		return model.NewASCIIArt(lines)
+	}
 }
 
 func (r *FileASCIIArtRepository) Save(path string, art *model.ASCIIArt) error {
@@ -42,7 +67,10 @@ func (r *FileASCIIArtRepository) Save(path string, art *model.ASCIIArt) error {
 	defer file.Close()
 
 	writer := bufio.NewWriter(file)
-	for _, line := range art.Lines() {
+	for i, line := range art.Lines() {
+		if art.HasColor() {
+			line = formatColoredLine(line, art.GetColors(i), art.GetBackgrounds(i))
+		}
 		if _, err := writer.WriteString(line + "\n"); err != nil {
 			return fmt.Errorf("ファイル書き込みエラー: %w", err)
 		}
@@ -50,3 +78,125 @@ func (r *FileASCIIArtRepository) Save(path string, art *model.ASCIIArt) error {
 
 	return writer.Flush()
 }
+
+// cellStyle は1文字分の文字色と背景色です。
+type cellStyle struct {
+	fg, bg model.RGB
+}
+
+// formatColoredLine は色が変わる位置にだけ色の指定を挟み、行末で色を戻します。backgrounds が nil なら背景色は書きません。
+func formatColoredLine(line string, colors, backgrounds []model.RGB) string {
+	var b strings.Builder
+	i := 0
+	var current cellStyle
+	for _, ch := range line {
+		style := cellStyle{fg: colors[i]}
+		if backgrounds != nil {
+			style.bg = backgrounds[i]
+		}
+		if i == 0 || style != current {
+			if backgrounds != nil {
+				fmt.Fprintf(&b, "\x1b[38;2;%d;%d;%d;48;2;%d;%d;%dm", style.fg.R, style.fg.G, style.fg.B, style.bg.R, style.bg.G, style.bg.B)
+			} else {
+				fmt.Fprintf(&b, "\x1b[38;2;%d;%d;%dm", style.fg.R, style.fg.G, style.fg.B)
+			}
+			current = style
+		}
+		b.WriteRune(ch)
+		i++
+	}
+	if i > 0 {
+		b.WriteString("\x1b[0m")
+	}
+	return b.String()
+}
+
+// parsedLine は色付きの行から読み取った文字と、文字ごとの色です。
+type parsedLine struct {
+	text        string
+	colors      []model.RGB
+	backgrounds []model.RGB
+	// colored と backgrounded は truecolor の文字色・背景色の指定があったかどうかです
+	colored      bool
+	backgrounded bool
+}
+
+// parseColoredLine は行から ANSI のエスケープシーケンスを取り除き、文字ごとの色を返します。
+// truecolor 以外の指定は無視し、色の指定がない文字は uncoloredCell と uncoloredBackground にします。
+func parseColoredLine(raw string) parsedLine {
+	var text strings.Builder
+	parsed := parsedLine{
+		colors:      make([]model.RGB, 0, len(raw)),
+		backgrounds: make([]model.RGB, 0, len(raw)),
+	}
+	current := cellStyle{fg: uncoloredCell, bg: uncoloredBackground}
+
+	for i := 0; i < len(raw); {
+		if raw[i] == '\x1b' && i+1 < len(raw) && raw[i+1] == '[' {
+			end := i + 2
+			for end < len(raw) && (raw[end] < 0x40 || raw[end] > 0x7e) {
+				end++
+			}
+			if end == len(raw) {
+				break
+			}
+			if raw[end] == 'm' {
+				var fgSet, bgSet bool
+				current, fgSet, bgSet = applySGR(raw[i+2:end], current)
+				parsed.colored = parsed.colored || fgSet
+				parsed.backgrounded = parsed.backgrounded || bgSet
+			}
+			i = end + 1
+			continue
+		}
+
+		ch, size := utf8.DecodeRuneInString(raw[i:])
+		text.WriteRune(ch)
+		parsed.colors = append(parsed.colors, current.fg)
+		parsed.backgrounds = append(parsed.backgrounds, current.bg)
+		i += size
+	}
+	parsed.text = text.String()
+	return parsed
+}
+
+// applySGR は SGR のパラメータを読んで変化後の文字色と背景色を返します。truecolor の指定があれば fgSet、bgSet は true です。
+func applySGR(params string, current cellStyle) (style cellStyle, fgSet, bgSet bool) {
+	fields := strings.Split(params, ";")
+	for i := 0; i < len(fields); i++ {
+		switch fields[i] {
+		case "", "0":
+			current = cellStyle{fg: uncoloredCell, bg: uncoloredBackground}
+		case "39":
+			current.fg = uncoloredCell
+		case "49":
+			current.bg = uncoloredBackground
+		case "38", "48":
+			if i+4 < len(fields) && fields[i+1] == "2" {
+				if rgb, ok := parseRGBFields(fields[i+2 : i+5]); ok {
+					if fields[i] == "38" {
+						current.fg = rgb
+						fgSet = true
+					} else {
+						current.bg = rgb
+						bgSet = true
+					}
+				}
+				i += 4
+			}
+		}
+	}
+	return current, fgSet, bgSet
+}
+
+func parseRGBFields(fields []string) (model.RGB, bool) {
+	var values [3]uint8
+	for j := range values {
+		v, err := strconv.ParseUint(fields[j], 10, 8)
+		if err != nil {
+			return model.RGB{}, false
+		}
+		values[j] = uint8(v)
+	}
+	return model.RGB{R: values[0], G: values[1], B: values[2]}, true
+}
@@ -3,6 +3,8 @@ package persistence
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"nyagoPing/internal/domain/model"
@@ -58,3 +60,103 @@ func TestFileASCIIArtRepository_Load_NotFound(t *testing.T) {
 		t.Error("Load() 存在しないファイルでエラーが発生しませんでした")
 	}
 }
+
+func TestFileASCIIArtRepository_Save_Load_Color(t *testing.T) {
+	repo := NewFileASCIIArtRepository()
+	testFile := filepath.Join(t.TempDir(), "color_art.txt")
+
+	red, blue := model.RGB{R: 255}, model.RGB{B: 255}
+	art, err := model.NewColoredASCIIArt(
+		[]string{"ねこ$", "", "ab"},
+		[][]model.RGB{{red, red, blue}, {}, {blue, red}},
+	)
+	if err != nil {
+		t.Fatalf("NewColoredASCIIArt() error = %v", err)
+	}
+	if err := repo.Save(testFile, art); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(testFile)
+	if want := "\x1b[38;2;255;0;0mねこ\x1b[38;2;0;0;255m$\x1b[0m\n"; !strings.HasPrefix(string(data), want) {
+		t.Errorf("1行目 = %q, want %q", strings.SplitAfter(string(data), "\n")[0], want)
+	}
+
+	loaded, err := repo.Load(testFile)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Lines(), art.Lines()) {
+		t.Errorf("Load() Lines = %q, want %q", loaded.Lines(), art.Lines())
+	}
+	for i := range art.Lines() {
+		if got, want := loaded.GetColors(i), art.GetColors(i); !reflect.DeepEqual(got, want) {
+			t.Errorf("Load() colors[%d] = %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestParseColoredLine(t *testing.T) {
+	red := model.RGB{R: 255}
+	tests := []struct {
+		name        string
+		raw         string
+		wantText    string
+		wantColors  []model.RGB
+		wantColored bool
+	}{
+		{"色なし", "ab", "ab", []model.RGB{uncoloredCell, uncoloredCell}, false},
+		{"リセットのみ", "a\x1b[0mb", "ab", []model.RGB{uncoloredCell, uncoloredCell}, false},
+		{"太字と組み合わせ", "\x1b[1;38;2;255;0;0ma\x1b[mb", "ab", []model.RGB{red, uncoloredCell}, true},
+		{"256色は無視", "\x1b[38;5;196ma", "a", []model.RGB{uncoloredCell}, false},
+		{"不正な値", "\x1b[38;2;300;0;0ma", "a", []model.RGB{uncoloredCell}, false},
+		{"途切れたシーケンス", "a\x1b[38;2", "a", []model.RGB{uncoloredCell}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseColoredLine(tt.raw)
+			if got.text != tt.wantText || got.colored != tt.wantColored || !reflect.DeepEqual(got.colors, tt.wantColors) {
+				t.Errorf("parseColoredLine(%q) = %q, %v, %v, want %q, %v, %v",
+					tt.raw, got.text, got.colors, got.colored, tt.wantText, tt.wantColors, tt.wantColored)
+			}
+			if got.backgrounded {
+				t.Errorf("parseColoredLine(%q) が背景色を読み取りました", tt.raw)
+			}
+		})
+	}
+}
+
+func TestFileASCIIArtRepository_Save_Load_Background(t *testing.T) {
+	repo := NewFileASCIIArtRepository()
+	testFile := filepath.Join(t.TempDir(), "block_art.txt")
+
+	red, blue := model.RGB{R: 255}, model.RGB{B: 255}
+	art, err := model.NewColoredASCIIArtWithBackground(
+		[]string{"▀▀▀"},
+		[][]model.RGB{{red, red, blue}},
+		[][]model.RGB{{blue, blue, blue}},
+	)
+	if err != nil {
+		t.Fatalf("NewColoredASCIIArtWithBackground() error = %v", err)
+	}
+	if err := repo.Save(testFile, art); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(testFile)
+	if want := "\x1b[38;2;255;0;0;48;2;0;0;255m▀▀\x1b[38;2;0;0;255;48;2;0;0;255m▀\x1b[0m\n"; string(data) != want {
+		t.Errorf("保存内容 = %q, want %q", data, want)
+	}
+
+	loaded, err := repo.Load(testFile)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !loaded.HasBackground() {
+		t.Fatal("Load() で背景色が失われました")
+	}
+	if !reflect.DeepEqual(loaded.GetColors(0), art.GetColors(0)) || !reflect.DeepEqual(loaded.GetBackgrounds(0), art.GetBackgrounds(0)) {
+		t.Errorf("Load() colors = %v / %v, want %v / %v",
+			loaded.GetColors(0), loaded.GetBackgrounds(0), art.GetColors(0), art.GetBackgrounds(0))
+	}
+}
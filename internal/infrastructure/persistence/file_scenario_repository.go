@@ -0,0 +1,79 @@
+package persistence
+
+import (
+	"encoding/json"
+	"fmt"
+	"nyagoPing/internal/domain/model"
+	"nyagoPing/internal/domain/repository"
+	"os"
+	"time"
+)
+
+// scenarioFile はシナリオファイル(JSON)の形式です。時間は "20ms" のような文字列で書きます。
+//
+//	{
+//	  "seed": 42,
+//	  "rtt": {"distribution": "normal", "mean": "20ms", "jitter": "5ms"},
+//	  "loss": 0.02,
+//	  "lossBursts": [{"start": 10, "length": 3}],
+//	  "duplicate": 0.01
+//	}
+type scenarioFile struct {
+	Seed int64 `json:"seed"`
+	Rtt  struct {
+		Distribution string `json:"distribution"`
+		Mean         string `json:"mean"`
+		Jitter       string `json:"jitter"`
+	} `json:"rtt"`
+	Loss       float64 `json:"loss"`
+	LossBursts []struct {
+		Start  int `json:"start"`
+		Length int `json:"length"`
+	} `json:"lossBursts"`
+	Duplicate float64 `json:"duplicate"`
+}
+
+type FileScenarioRepository struct{}
+
+func NewFileScenarioRepository() repository.ScenarioRepository {
+	return &FileScenarioRepository{}
+}
+
+func (r *FileScenarioRepository) Load(path string) (*model.SimulationScenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ファイルを開けません: %w", err)
+	}
+
+	var file scenarioFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("シナリオファイルの解析エラー: %w", err)
+	}
+
+	distribution, err := model.ParseRttDistribution(file.Rtt.Distribution)
+	if err != nil {
+		return nil, err
+	}
+	mean, err := parseDurationField(file.Rtt.Mean)
+	if err != nil {
+		return nil, fmt.Errorf("rtt.mean の解析エラー: %w", err)
+	}
+	jitter, err := parseDurationField(file.Rtt.Jitter)
+	if err != nil {
+		return nil, fmt.Errorf("rtt.jitter の解析エラー: %w", err)
+	}
+
+	bursts := make([]model.LossBurst, 0, len(file.LossBursts))
+	for _, b := range file.LossBursts {
+		bursts = append(bursts, model.LossBurst{Start: b.Start, Length: b.Length})
+	}
+
+	return model.NewSimulationScenario(file.Seed, distribution, mean, jitter, file.Loss, bursts, file.Duplicate)
+}
+
+func parseDurationField(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(s)
+}
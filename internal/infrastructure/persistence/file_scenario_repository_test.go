@@ -0,0 +1,77 @@
+package persistence
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nyagoPing/internal/domain/model"
+)
+
+func TestFileScenarioRepository_Load(t *testing.T) {
+	repo := NewFileScenarioRepository()
+
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "scenario.json")
+	content := `{
+  "seed": 42,
+  "rtt": {"distribution": "uniform", "mean": "30ms", "jitter": "10ms"},
+  "loss": 0.1,
+  "lossBursts": [{"start": 5, "length": 3}],
+  "duplicate": 0.05
+}`
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatalf("テストファイルの作成エラー: %v", err)
+	}
+
+	scenario, err := repo.Load(testFile)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if scenario.Seed() != 42 {
+		t.Errorf("Seed() = %v, want 42", scenario.Seed())
+	}
+	if scenario.Distribution() != model.RttDistributionUniform {
+		t.Errorf("Distribution() = %v, want uniform", scenario.Distribution())
+	}
+	if scenario.RttMean() != 30*time.Millisecond || scenario.RttJitter() != 10*time.Millisecond {
+		t.Errorf("RttMean/RttJitter = %v/%v, want 30ms/10ms", scenario.RttMean(), scenario.RttJitter())
+	}
+	if scenario.LossRate() != 0.1 || scenario.DuplicateRate() != 0.05 {
+		t.Errorf("LossRate/DuplicateRate = %v/%v, want 0.1/0.05", scenario.LossRate(), scenario.DuplicateRate())
+	}
+	if !scenario.InLossBurst(6) {
+		t.Error("InLossBurst(6) = false, want true")
+	}
+}
+
+func TestFileScenarioRepository_Load_Invalid(t *testing.T) {
+	repo := NewFileScenarioRepository()
+	tmpDir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "JSONではない", content: "nyago"},
+		{name: "時間の形式が不正", content: `{"rtt": {"mean": "fast"}}`},
+		{name: "平均RTTなし", content: `{"loss": 0.1}`},
+		{name: "不明な分布", content: `{"rtt": {"distribution": "poisson", "mean": "1ms"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFile := filepath.Join(tmpDir, "invalid.json")
+			os.WriteFile(testFile, []byte(tt.content), 0644)
+			if _, err := repo.Load(testFile); err == nil {
+				t.Error("Load() 不正なシナリオでエラーが発生しませんでした")
+			}
+		})
+	}
+
+	if _, err := repo.Load(filepath.Join(tmpDir, "non_existent.json")); err == nil {
+		t.Error("Load() 存在しないファイルでエラーが発生しませんでした")
+	}
+}
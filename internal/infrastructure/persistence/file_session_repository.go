@@ -0,0 +1,299 @@
+package persistence
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"nyagoPing/internal/domain/model"
+	"nyagoPing/internal/domain/repository"
+	"os"
+	"sync"
+	"time"
+)
+
+// sessionLine はセッションファイル(JSON Lines)の1行の形式です。時間はナノ秒で書きます。
+type sessionLine struct {
+	Time       time.Time          `json:"time"`
+	Host       string             `json:"host"`
+	Packet     *sessionPacket     `json:"packet,omitempty"`
+	Event      *sessionEvent      `json:"event,omitempty"`
+	Statistics *sessionStatistics `json:"statistics,omitempty"`
+}
+
+type sessionPacket struct {
+	Seq        int           `json:"seq"`
+	Bytes      int           `json:"bytes"`
+	IP         string        `json:"ip"`
+	TTL        int           `json:"ttl"`
+	Rtt        time.Duration `json:"rtt"`
+	ArtLine    string        `json:"artLine"`
+	StatusCode int           `json:"statusCode,omitempty"`
+	sessionArtColors
+}
+
+type sessionEvent struct {
+	Type       string        `json:"type"`
+	Seq        int           `json:"seq"`
+	ArtLine    string        `json:"artLine"`
+	Rtt        time.Duration `json:"rtt,omitempty"`
+	StatusCode int           `json:"statusCode,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	sessionArtColors
+}
+
+// sessionArtColors は色付きのアートの行の文字ごとの色と背景色です。色は "#rrggbb" で書きます。
+type sessionArtColors struct {
+	ArtColors      []string `json:"artColors,omitempty"`
+	ArtBackgrounds []string `json:"artBackgrounds,omitempty"`
+}
+
+func newSessionArtColors(colors, backgrounds []model.RGB) sessionArtColors {
+	return sessionArtColors{ArtColors: formatHexColors(colors), ArtBackgrounds: formatHexColors(backgrounds)}
+}
+
+func (c sessionArtColors) parse() ([]model.RGB, []model.RGB, error) {
+	colors, err := parseHexColors(c.ArtColors)
+	if err != nil {
+		return nil, nil, err
+	}
+	backgrounds, err := parseHexColors(c.ArtBackgrounds)
+	if err != nil {
+		return nil, nil, err
+	}
+	return colors, backgrounds, nil
+}
+
+// formatHexColors は色を "#rrggbb" の並びにします。colors が nil なら nil を返します。
+func formatHexColors(colors []model.RGB) []string {
+	if colors == nil {
+		return nil
+	}
+	hex := make([]string, len(colors))
+	for i, c := range colors {
+		hex[i] = fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return hex
+}
+
+func parseHexColors(hex []string) ([]model.RGB, error) {
+	if hex == nil {
+		return nil, nil
+	}
+	colors := make([]model.RGB, len(hex))
+	for i, h := range hex {
+		var c model.RGB
+		if len(h) != 7 {
+			return nil, fmt.Errorf("色の形式が不正です: %q", h)
+		}
+		if _, err := fmt.Sscanf(h, "#%02x%02x%02x", &c.R, &c.G, &c.B); err != nil {
+			return nil, fmt.Errorf("色の形式が不正です: %q", h)
+		}
+		colors[i] = c
+	}
+	return colors, nil
+}
+
+type sessionStatistics struct {
+	Addr       string             `json:"addr"`
+	Sent       int                `json:"sent"`
+	Recv       int                `json:"recv"`
+	Duplicates int                `json:"duplicates"`
+	Loss       float64            `json:"loss"`
+	MinRtt     time.Duration      `json:"minRtt"`
+	AvgRtt     time.Duration      `json:"avgRtt"`
+	MaxRtt     time.Duration      `json:"maxRtt"`
+	StdDevRtt  time.Duration      `json:"stdDevRtt"`
+	P50Rtt     time.Duration      `json:"p50Rtt"`
+	P90Rtt     time.Duration      `json:"p90Rtt"`
+	P95Rtt     time.Duration      `json:"p95Rtt"`
+	P99Rtt     time.Duration      `json:"p99Rtt"`
+	Jitter     time.Duration      `json:"jitter"`
+	Histogram  []sessionRttBucket `json:"histogram,omitempty"`
+}
+
+type sessionRttBucket struct {
+	Lower time.Duration `json:"lower"`
+	Upper time.Duration `json:"upper"`
+	Count int           `json:"count"`
+}
+
+type FileSessionRepository struct{}
+
+func NewFileSessionRepository() repository.SessionRepository {
+	return &FileSessionRepository{}
+}
+
+func (r *FileSessionRepository) Create(path string) (repository.SessionRecorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("ファイルを作成できません: %w", err)
+	}
+	return &fileSessionRecorder{
+		file:    file,
+		encoder: json.NewEncoder(file),
+	}, nil
+}
+
+func (r *FileSessionRepository) Load(path string) (*model.Session, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("ファイルを開けません: %w", err)
+	}
+	defer file.Close()
+
+	var records []*model.SessionRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var line sessionLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return nil, fmt.Errorf("%d行目の解析エラー: %w", lineNo, err)
+		}
+		record, err := line.toRecord()
+		if err != nil {
+			return nil, fmt.Errorf("%d行目: %w", lineNo, err)
+		}
+		records = append(records, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ファイル読み込みエラー: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("セッションが空です: %s", path)
+	}
+
+	return model.NewSession(records), nil
+}
+
+func (l *sessionLine) toRecord() (*model.SessionRecord, error) {
+	record := &model.SessionRecord{
+		Time: l.Time,
+		Host: l.Host,
+	}
+	switch {
+	case l.Packet != nil:
+		p := l.Packet
+		colors, backgrounds, err := p.parse()
+		if err != nil {
+			return nil, err
+		}
+		record.Packet = model.NewPingPacket(p.Seq, p.Bytes, p.TTL, net.ParseIP(p.IP), p.Rtt, p.ArtLine)
+		record.Packet.StatusCode = p.StatusCode
+		record.Packet.ArtColors = colors
+		record.Packet.ArtBackgrounds = backgrounds
+	case l.Event != nil:
+		e := l.Event
+		eventType, err := model.ParsePingEventType(e.Type)
+		if err != nil {
+			return nil, err
+		}
+		colors, backgrounds, err := e.parse()
+		if err != nil {
+			return nil, err
+		}
+		var eventErr error
+		if e.Error != "" {
+			eventErr = errors.New(e.Error)
+		}
+		record.Event = model.NewPingEvent(eventType, e.Seq, e.ArtLine, eventErr)
+		record.Event.Rtt = e.Rtt
+		record.Event.StatusCode = e.StatusCode
+		record.Event.ArtColors = colors
+		record.Event.ArtBackgrounds = backgrounds
+	case l.Statistics != nil:
+		s := l.Statistics
+		record.Statistics = model.NewPingStatistics(s.Addr, s.Sent, s.Recv, s.Loss, s.MinRtt, s.AvgRtt, s.MaxRtt, s.StdDevRtt)
+		record.Statistics.PacketsDuplicates = s.Duplicates
+		record.Statistics.P50Rtt = s.P50Rtt
+		record.Statistics.P90Rtt = s.P90Rtt
+		record.Statistics.P95Rtt = s.P95Rtt
+		record.Statistics.P99Rtt = s.P99Rtt
+		record.Statistics.Jitter = s.Jitter
+		for _, b := range s.Histogram {
+			record.Statistics.Histogram = append(record.Statistics.Histogram, model.RttBucket{Lower: b.Lower, Upper: b.Upper, Count: b.Count})
+		}
+	default:
+		return nil, fmt.Errorf("packet・event・statistics のいずれもありません")
+	}
+	return record, nil
+}
+
+type fileSessionRecorder struct {
+	mu      sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+}
+
+func (r *fileSessionRecorder) Record(record *model.SessionRecord) error {
+	line := sessionLine{
+		Time: record.Time,
+		Host: record.Host,
+	}
+	if p := record.Packet; p != nil {
+		line.Packet = &sessionPacket{
+			Seq:              p.Seq,
+			Bytes:            p.Nbytes,
+			TTL:              p.TTL,
+			Rtt:              p.Rtt,
+			ArtLine:          p.ArtLine,
+			StatusCode:       p.StatusCode,
+			sessionArtColors: newSessionArtColors(p.ArtColors, p.ArtBackgrounds),
+		}
+		if p.IPAddr != nil {
+			line.Packet.IP = p.IPAddr.String()
+		}
+	}
+	if e := record.Event; e != nil {
+		line.Event = &sessionEvent{
+			Type:             e.Type.String(),
+			Seq:              e.Seq,
+			ArtLine:          e.ArtLine,
+			Rtt:              e.Rtt,
+			StatusCode:       e.StatusCode,
+			sessionArtColors: newSessionArtColors(e.ArtColors, e.ArtBackgrounds),
+		}
+		if e.Err != nil {
+			line.Event.Error = e.Err.Error()
+		}
+	}
+	if s := record.Statistics; s != nil {
+		line.Statistics = &sessionStatistics{
+			Addr:       s.Addr,
+			Sent:       s.PacketsSent,
+			Recv:       s.PacketsRecv,
+			Duplicates: s.PacketsDuplicates,
+			Loss:       s.PacketLoss,
+			MinRtt:     s.MinRtt,
+			AvgRtt:     s.AvgRtt,
+			MaxRtt:     s.MaxRtt,
+			StdDevRtt:  s.StdDevRtt,
+			P50Rtt:     s.P50Rtt,
+			P90Rtt:     s.P90Rtt,
+			P95Rtt:     s.P95Rtt,
+			P99Rtt:     s.P99Rtt,
+			Jitter:     s.Jitter,
+		}
+		for _, b := range s.Histogram {
+			line.Statistics.Histogram = append(line.Statistics.Histogram, sessionRttBucket{Lower: b.Lower, Upper: b.Upper, Count: b.Count})
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.encoder.Encode(&line); err != nil {
+		return fmt.Errorf("ファイル書き込みエラー: %w", err)
+	}
+	return nil
+}
+
+func (r *fileSessionRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
@@ -0,0 +1,166 @@
+package persistence
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"nyagoPing/internal/domain/model"
+)
+
+func TestFileSessionRepository_Create_Load(t *testing.T) {
+	repo := NewFileSessionRepository()
+
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "session.jsonl")
+
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	packet := model.NewPingPacket(3, 64, 57, net.ParseIP("192.0.2.1"), 12*time.Millisecond, "  ███╗")
+	packet.StatusCode = 200
+	stats := model.CalculatePingStatistics("example.tld", 4, []time.Duration{time.Millisecond, 3 * time.Millisecond, 2 * time.Millisecond})
+	stats.PacketsDuplicates = 1
+	event := model.NewPingEvent(model.PingEventLost, 4, "  ╚══╝", errors.New("connection refused"))
+	event.StatusCode = 503
+
+	recorder, err := repo.Create(testFile)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := recorder.Record(&model.SessionRecord{Time: start, Host: "example.tld", Packet: packet}); err != nil {
+		t.Errorf("Record() error = %v", err)
+	}
+	if err := recorder.Record(&model.SessionRecord{Time: start.Add(time.Second), Host: "example.tld", Event: event}); err != nil {
+		t.Errorf("Record() error = %v", err)
+	}
+	if err := recorder.Record(&model.SessionRecord{Time: start.Add(2 * time.Second), Host: "example.tld", Statistics: stats}); err != nil {
+		t.Errorf("Record() error = %v", err)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+
+	session, err := repo.Load(testFile)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	records := session.Records()
+	if len(records) != 3 {
+		t.Fatalf("Records() = %d件, want 3件", len(records))
+	}
+
+	got := records[0]
+	if !got.Time.Equal(start) || got.Host != "example.tld" || got.Packet == nil {
+		t.Fatalf("records[0] = %+v", got)
+	}
+	if got.Packet.Seq != 3 || got.Packet.Nbytes != 64 || got.Packet.TTL != 57 || got.Packet.Rtt != 12*time.Millisecond {
+		t.Errorf("packet = %+v", got.Packet)
+	}
+	if got.Packet.ArtLine != "  ███╗" || got.Packet.StatusCode != 200 || !got.Packet.IPAddr.Equal(net.ParseIP("192.0.2.1")) {
+		t.Errorf("packet = %+v", got.Packet)
+	}
+
+	gotEvent := records[1].Event
+	if gotEvent == nil {
+		t.Fatalf("records[1] = %+v", records[1])
+	}
+	if gotEvent.Type != model.PingEventLost || gotEvent.Seq != 4 || gotEvent.ArtLine != "  ╚══╝" || gotEvent.Err == nil || gotEvent.Err.Error() != "connection refused" || gotEvent.StatusCode != 503 {
+		t.Errorf("event = %+v", gotEvent)
+	}
+
+	gotStats := records[2].Statistics
+	if gotStats == nil {
+		t.Fatalf("records[2] = %+v", records[2])
+	}
+	if !reflect.DeepEqual(gotStats, stats) {
+		t.Errorf("statistics = %+v, want %+v", gotStats, stats)
+	}
+}
+
+func TestFileSessionRepository_Create_Load_ColoredArt(t *testing.T) {
+	repo := NewFileSessionRepository()
+	testFile := filepath.Join(t.TempDir(), "session.jsonl")
+
+	art, err := model.NewColoredASCIIArtWithBackground(
+		[]string{"▀▀", "▀▀"},
+		[][]model.RGB{{{R: 255}, {G: 128}}, {{B: 64}, {R: 1, G: 2, B: 3}}},
+		[][]model.RGB{{{R: 10}, {G: 20}}, {{B: 30}, {R: 40}}},
+	)
+	if err != nil {
+		t.Fatalf("NewColoredASCIIArtWithBackground() error = %v", err)
+	}
+	packet := model.NewPingPacket(0, 64, 57, net.ParseIP("192.0.2.1"), time.Millisecond, art.GetLineBySeq(0))
+	packet.ArtColors = art.GetColorsBySeq(0)
+	packet.ArtBackgrounds = art.GetBackgroundsBySeq(0)
+	event := model.NewPingEvent(model.PingEventTimeout, 1, art.GetLineBySeq(1), nil)
+	event.ArtColors = art.GetColorsBySeq(1)
+	event.ArtBackgrounds = art.GetBackgroundsBySeq(1)
+	plain := model.NewPingPacket(2, 64, 57, net.ParseIP("192.0.2.1"), time.Millisecond, "plain")
+
+	recorder, err := repo.Create(testFile)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, record := range []*model.SessionRecord{
+		{Time: start, Host: "a.tld", Packet: packet},
+		{Time: start, Host: "a.tld", Event: event},
+		{Time: start, Host: "a.tld", Packet: plain},
+	} {
+		if err := recorder.Record(record); err != nil {
+			t.Errorf("Record() error = %v", err)
+		}
+	}
+	if err := recorder.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+
+	session, err := repo.Load(testFile)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	records := session.Records()
+	if len(records) != 3 {
+		t.Fatalf("Records() = %d件, want 3件", len(records))
+	}
+	if got := records[0].Packet; !reflect.DeepEqual(got.ArtColors, packet.ArtColors) || !reflect.DeepEqual(got.ArtBackgrounds, packet.ArtBackgrounds) {
+		t.Errorf("packet の色 = %v / %v, want %v / %v", got.ArtColors, got.ArtBackgrounds, packet.ArtColors, packet.ArtBackgrounds)
+	}
+	if got := records[1].Event; !reflect.DeepEqual(got.ArtColors, event.ArtColors) || !reflect.DeepEqual(got.ArtBackgrounds, event.ArtBackgrounds) {
+		t.Errorf("event の色 = %v / %v, want %v / %v", got.ArtColors, got.ArtBackgrounds, event.ArtColors, event.ArtBackgrounds)
+	}
+	// 色のないアートは色のないまま読み戻す
+	if got := records[2].Packet; got.ArtColors != nil || got.ArtBackgrounds != nil {
+		t.Errorf("色のない packet の色 = %v / %v, want nil", got.ArtColors, got.ArtBackgrounds)
+	}
+}
+
+func TestFileSessionRepository_Load_Invalid(t *testing.T) {
+	repo := NewFileSessionRepository()
+	tmpDir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "空", content: ""},
+		{name: "JSONではない", content: "nyago\n"},
+		{name: "中身のない行", content: `{"time":"2024-01-02T03:04:05Z","host":"a"}` + "\n"},
+		{name: "不明なイベント", content: `{"time":"2024-01-02T03:04:05Z","host":"a","event":{"type":"nyago","seq":0}}` + "\n"},
+		{name: "不正な色", content: `{"time":"2024-01-02T03:04:05Z","host":"a","packet":{"seq":0,"artColors":["red"]}}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFile := filepath.Join(tmpDir, "invalid.jsonl")
+			os.WriteFile(testFile, []byte(tt.content), 0644)
+			if _, err := repo.Load(testFile); err == nil {
+				t.Error("Load() 不正なセッションでエラーが発生しませんでした")
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package persistence
+
+import (
+	"encoding/json"
+	"fmt"
+	"nyagoPing/internal/domain/model"
+	"nyagoPing/internal/domain/repository"
+	"os"
+)
+
+// thresholdFile は閾値ファイル(JSON)の形式です。省略した項目は既定値になります。
+//
+//	{"warn": "30ms", "bad": "100ms"}
+type thresholdFile struct {
+	Warn string `json:"warn"`
+	Bad  string `json:"bad"`
+}
+
+type FileThresholdRepository struct{}
+
+func NewFileThresholdRepository() repository.ThresholdRepository {
+	return &FileThresholdRepository{}
+}
+
+func (r *FileThresholdRepository) Load(path string) (*model.RttThresholds, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ファイルを開けません: %w", err)
+	}
+
+	var file thresholdFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("閾値ファイルの解析エラー: %w", err)
+	}
+
+	warn, err := parseDurationField(file.Warn)
+	if err != nil {
+		return nil, fmt.Errorf("warn の解析エラー: %w", err)
+	}
+	if warn == 0 {
+		warn = model.DefaultWarnRtt
+	}
+	bad, err := parseDurationField(file.Bad)
+	if err != nil {
+		return nil, fmt.Errorf("bad の解析エラー: %w", err)
+	}
+	if bad == 0 {
+		bad = model.DefaultBadRtt
+	}
+
+	return model.NewRttThresholds(warn, bad)
+}
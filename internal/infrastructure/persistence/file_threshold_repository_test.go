@@ -0,0 +1,45 @@
+package persistence
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileThresholdRepository_Load(t *testing.T) {
+	repo := NewFileThresholdRepository()
+	tmpDir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		content  string
+		wantWarn time.Duration
+		wantBad  time.Duration
+		wantErr  bool
+	}{
+		{name: "両方指定", content: `{"warn": "10ms", "bad": "50ms"}`, wantWarn: 10 * time.Millisecond, wantBad: 50 * time.Millisecond},
+		{name: "省略は既定値", content: `{"bad": "200ms"}`, wantWarn: 30 * time.Millisecond, wantBad: 200 * time.Millisecond},
+		{name: "JSONではない", content: "nyago", wantErr: true},
+		{name: "時間の形式が不正", content: `{"warn": "fast"}`, wantErr: true},
+		{name: "大小が逆", content: `{"warn": "200ms", "bad": "100ms"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFile := filepath.Join(tmpDir, "thresholds.json")
+			os.WriteFile(testFile, []byte(tt.content), 0644)
+
+			thresholds, err := repo.Load(testFile)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if thresholds.Warn() != tt.wantWarn || thresholds.Bad() != tt.wantBad {
+				t.Errorf("warn/bad = %v/%v, want %v/%v", thresholds.Warn(), thresholds.Bad(), tt.wantWarn, tt.wantBad)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package ping
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"nyagoPing/internal/domain/model"
+	"nyagoPing/internal/domain/repository"
+)
+
+// newProtocolTestConfig は protocol で port へ count 回送る計測設定を作ります。送信間隔は10ミリ秒です。
+func newProtocolTestConfig(t *testing.T, protocol model.Protocol, port, count int, timeout time.Duration) *model.PingConfig {
+	t.Helper()
+	config, err := model.NewPingConfig(count, false, 10*time.Millisecond, timeout, 0)
+	if err != nil {
+		t.Fatalf("NewPingConfig() error = %v", err)
+	}
+	if err := config.SetProtocol(protocol, port); err != nil {
+		t.Fatalf("SetProtocol() error = %v", err)
+	}
+	return config
+}
+
+// testArt は "line1" から始まる n 行のアートを作ります。
+func testArt(n int) *model.ASCIIArt {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line%d", i+1)
+	}
+	art, _ := model.NewASCIIArt(lines)
+	return art
+}
+
+// collectPing は repo で host へ計測し、コールバックで受け取った応答・イベント・統計を返します。
+// アートは testArt(config.Count()) です。
+func collectPing(t *testing.T, repo repository.PingRepository, host string, config *model.PingConfig) ([]*model.PingPacket, []*model.PingEvent, *model.PingStatistics) {
+	t.Helper()
+	target, err := model.NewPingTarget(host)
+	if err != nil {
+		t.Fatalf("NewPingTarget() error = %v", err)
+	}
+
+	var packets []*model.PingPacket
+	var events []*model.PingEvent
+	var stats *model.PingStatistics
+	err = repo.Ping(context.Background(), target, config, testArt(config.Count()),
+		func(packet *model.PingPacket) {
+			packets = append(packets, packet)
+		},
+		func(event *model.PingEvent) {
+			events = append(events, event)
+		},
+		func(s *model.PingStatistics) {
+			stats = s
+		},
+	)
+	if err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	return packets, events, stats
+}
@@ -0,0 +1,129 @@
+package ping
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"nyagoPing/internal/domain/model"
+	"nyagoPing/internal/domain/repository"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// HTTPRepository はURLへHEADまたはGETリクエストを送り、最初の1バイトが届くまでの時間をRTTとして計測します。
+// 2xx以外のステータスコードはロスとして扱います。
+type HTTPRepository struct{}
+
+func NewHTTPRepository() repository.PingRepository {
+	return &HTTPRepository{}
+}
+
+func (r *HTTPRepository) Ping(
+	ctx context.Context,
+	target *model.PingTarget,
+	config *model.PingConfig,
+	art *model.ASCIIArt,
+	onRecv func(*model.PingPacket),
+	onEvent func(*model.PingEvent),
+	onFinish func(*model.PingStatistics),
+) error {
+	u, err := url.Parse(target.Host())
+	if err != nil {
+		return fmt.Errorf("URLの解析エラー: %w", err)
+	}
+
+	hostTarget, err := model.NewPingTarget(u.Hostname())
+	if err != nil {
+		return fmt.Errorf("URLにホスト名がありません: %s", target.Host())
+	}
+	if err := resolveTarget(hostTarget, config.Family()); err != nil {
+		return err
+	}
+	target.SetIP(hostTarget.IP())
+
+	// http.ProxyFromEnvironment は最初に読んだ環境変数を使い続けるため、Ping ごとに読み直す
+	proxy := httpproxy.FromEnvironment().ProxyFunc()
+	origin := net.JoinHostPort(u.Hostname(), urlPort(u))
+	var dialer net.Dialer
+	transport := &http.Transport{
+		// 接続を使い回すと2回目以降のRTTに接続確立の時間が含まれなくなるため、プローブごとに接続し直す
+		DisableKeepAlives: true,
+		Proxy: func(req *http.Request) (*url.URL, error) {
+			return proxy(req.URL)
+		},
+		// 名前解決済みのアドレスに固定するのは対象のホストへの接続だけにし、プロキシへの接続はそのまま通す
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if addr != origin {
+				return dialer.DialContext(ctx, network, addr)
+			}
+			_, port, err := net.SplitHostPort(addr)
+			if err != nil {
+				return nil, err
+			}
+			return dialer.DialContext(ctx, dialNetwork("tcp", config.Family()), net.JoinHostPort(target.IP().String(), port))
+		},
+	}
+	defer transport.CloseIdleConnections()
+
+	client := &http.Client{
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	runProbes(ctx, target, config, art, func(ctx context.Context, seq int) (*probeResult, error) {
+		req, err := http.NewRequestWithContext(ctx, config.Method(), u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var start time.Time
+		var ttfb time.Duration
+		trace := &httptrace.ClientTrace{
+			GotFirstResponseByte: func() {
+				ttfb = time.Since(start)
+			},
+		}
+		req = req.WithContext(httptrace.WithClientTrace(ctx, trace))
+
+		start = time.Now()
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		nbytes, err := io.Copy(io.Discard, resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil, &statusError{code: resp.StatusCode}
+		}
+
+		return &probeResult{
+			nbytes:     int(nbytes),
+			rtt:        ttfb,
+			statusCode: resp.StatusCode,
+		}, nil
+	}, onRecv, onEvent, onFinish)
+
+	return nil
+}
+
+// urlPort は URL のポートを返します。省略されている場合はスキームの既定のポートです。
+func urlPort(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return port
+	}
+	if u.Scheme == "https" {
+		return "443"
+	}
+	return "80"
+}
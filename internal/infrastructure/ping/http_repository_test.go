@@ -0,0 +1,171 @@
+package ping
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"nyagoPing/internal/domain/model"
+)
+
+func TestHTTPRepository_Ping(t *testing.T) {
+	var mu sync.Mutex
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		methods = append(methods, r.Method)
+		mu.Unlock()
+		w.Write([]byte("nyago"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		method     string
+		wantMethod string
+		wantBytes  int
+	}{
+		{
+			name:       "HEAD",
+			method:     "",
+			wantMethod: http.MethodHead,
+			wantBytes:  0,
+		},
+		{
+			name:       "GET",
+			method:     "GET",
+			wantMethod: http.MethodGet,
+			wantBytes:  5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			methods = nil
+			mu.Unlock()
+			config := newProtocolTestConfig(t, model.ProtocolHTTP, 0, 2, time.Second)
+			if err := config.SetMethod(tt.method); err != nil {
+				t.Fatalf("SetMethod() error = %v", err)
+			}
+			packets, _, stats := collectPing(t, NewHTTPRepository(), server.URL, config)
+
+			if len(packets) != 2 {
+				t.Fatalf("受信数 = %d, want 2", len(packets))
+			}
+			for i, packet := range packets {
+				if packet.StatusCode != http.StatusOK {
+					t.Errorf("packet[%d].StatusCode = %d, want 200", i, packet.StatusCode)
+				}
+				if packet.Nbytes != tt.wantBytes {
+					t.Errorf("packet[%d].Nbytes = %d, want %d", i, packet.Nbytes, tt.wantBytes)
+				}
+				if packet.Rtt <= 0 {
+					t.Errorf("packet[%d].Rtt = %v", i, packet.Rtt)
+				}
+			}
+			mu.Lock()
+			for _, m := range methods {
+				if m != tt.wantMethod {
+					t.Errorf("method = %s, want %s", m, tt.wantMethod)
+				}
+			}
+			mu.Unlock()
+			if stats == nil || stats.PacketsRecv != 2 || stats.PacketLoss != 0 {
+				t.Errorf("stats = %+v, want 2受信 0%%ロス", stats)
+			}
+		})
+	}
+}
+
+func TestHTTPRepository_Ping_Non2xxIsLoss(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	for path, wantStatus := range map[string]int{"/": http.StatusServiceUnavailable, "/redirect": http.StatusFound} {
+		t.Run(path, func(t *testing.T) {
+			packets, events, stats := collectPing(t, NewHTTPRepository(), server.URL+path, newProtocolTestConfig(t, model.ProtocolHTTP, 0, 2, time.Second))
+			if len(packets) != 0 {
+				t.Errorf("2xx以外の応答を受信として扱いました: %+v", packets)
+			}
+			if stats == nil || stats.PacketsSent != 2 || stats.PacketLoss != 100 {
+				t.Errorf("stats = %+v, want 2送信 100%%ロス", stats)
+			}
+			if len(events) != 2 || events[0].Type != model.PingEventLost {
+				t.Fatalf("events = %+v, want 2件のロス", events)
+			}
+			for i, event := range events {
+				if event.StatusCode != wantStatus {
+					t.Errorf("events[%d].StatusCode = %d, want %d", i, event.StatusCode, wantStatus)
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPRepository_Ping_NewConnectionPerProbe(t *testing.T) {
+	var mu sync.Mutex
+	conns := 0
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			mu.Lock()
+			conns++
+			mu.Unlock()
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	config := newProtocolTestConfig(t, model.ProtocolHTTP, 0, 3, time.Second)
+	if err := config.SetMethod("GET"); err != nil {
+		t.Fatalf("SetMethod() error = %v", err)
+	}
+	packets, _, _ := collectPing(t, NewHTTPRepository(), server.URL, config)
+	if len(packets) != 3 {
+		t.Fatalf("受信数 = %d, want 3", len(packets))
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if conns != 3 {
+		t.Errorf("接続数 = %d, want 3 (プローブごとに新しい接続)", conns)
+	}
+}
+
+func TestHTTPRepository_Ping_Proxy(t *testing.T) {
+	// プロキシには絶対URIのリクエストが届く。対象のアドレスへ付け替えられていれば届かない
+	var mu sync.Mutex
+	var requested []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.String())
+		mu.Unlock()
+	}))
+	defer proxy.Close()
+	t.Setenv("HTTP_PROXY", proxy.URL)
+	t.Setenv("NO_PROXY", "")
+
+	// プロキシはループバックの宛先には使われないため、文書用のアドレスを対象にする
+	config := newProtocolTestConfig(t, model.ProtocolHTTP, 0, 1, time.Second)
+	if err := config.SetMethod("GET"); err != nil {
+		t.Fatalf("SetMethod() error = %v", err)
+	}
+	packets, events, _ := collectPing(t, NewHTTPRepository(), "http://192.0.2.1/nyago", config)
+	if len(packets) != 1 || packets[0].StatusCode != http.StatusOK {
+		t.Fatalf("packets = %+v, events = %+v, want プロキシ経由で1件受信", packets, events)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requested) != 1 || requested[0] != "http://192.0.2.1/nyago" {
+		t.Errorf("プロキシへのリクエスト = %v", requested)
+	}
+}
@@ -0,0 +1,158 @@
+package ping
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"nyagoPing/internal/domain/model"
+	"os"
+	"runtime"
+	"time"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// pingWithPattern は pro-bing の代わりに golang.org/x/net/icmp で Echo を送ります。
+// pro-bing はペイロードを 0x01 で埋めるため、ペイロードパターンを指定した場合はこちらで送ります。
+// 応答は ID(非特権ソケットではカーネルが書き換えるため除く)、シーケンス、ペイロードが一致したものだけを受信とします。
+func pingWithPattern(
+	ctx context.Context,
+	target *model.PingTarget,
+	config *model.PingConfig,
+	art *model.ASCIIArt,
+	onRecv func(*model.PingPacket),
+	onEvent func(*model.PingEvent),
+	onFinish func(*model.PingStatistics),
+) error {
+	if err := resolveTarget(target, config.Family()); err != nil {
+		return err
+	}
+
+	v6 := target.Family() == model.IPFamilyV6
+	privileged := config.Privileged() || runtime.GOOS == "windows"
+	network, address := icmpEchoNetwork(v6, privileged)
+	var dst net.Addr = &net.UDPAddr{IP: target.IP()}
+	if privileged {
+		dst = &net.IPAddr{IP: target.IP()}
+	}
+
+	id := os.Getpid() & 0xffff
+	payload := echoPayload(config.Size(), config.Pattern())
+
+	runProbes(ctx, target, config, art, func(ctx context.Context, seq int) (*probeResult, error) {
+		conn, err := icmp.ListenPacket(network, address)
+		if err != nil {
+			return nil, fmt.Errorf("ICMPソケットを開けません: %w", err)
+		}
+		defer conn.Close()
+		if err := setEchoSocketOptions(conn, v6, config.TTL()); err != nil {
+			return nil, err
+		}
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetReadDeadline(deadline)
+		}
+		stop := context.AfterFunc(ctx, func() {
+			conn.SetReadDeadline(time.Now())
+		})
+		defer stop()
+
+		msg := icmp.Message{Type: ipv4.ICMPTypeEcho, Body: &icmp.Echo{ID: id, Seq: seq & 0xffff, Data: payload}}
+		if v6 {
+			msg.Type = ipv6.ICMPTypeEchoRequest
+		}
+		b, err := msg.Marshal(nil)
+		if err != nil {
+			return nil, err
+		}
+
+		start := time.Now()
+		if _, err := conn.WriteTo(b, dst); err != nil {
+			return nil, err
+		}
+
+		buf := make([]byte, maxUDPResponseSize)
+		for {
+			n, ttl, err := readEcho(conn, v6, buf)
+			if err != nil {
+				return nil, err
+			}
+			if matchEchoReply(buf[:n], v6, id, seq, privileged, payload) {
+				return &probeResult{nbytes: n, ttl: ttl, rtt: time.Since(start)}, nil
+			}
+		}
+	}, onRecv, onEvent, onFinish)
+
+	return nil
+}
+
+// icmpEchoNetwork は icmp.ListenPacket に渡すネットワークとアドレスを返します。非特権では ICMP 用のデータグラムソケットを使います。
+func icmpEchoNetwork(v6, privileged bool) (string, string) {
+	switch {
+	case v6 && privileged:
+		return "ip6:ipv6-icmp", "::"
+	case v6:
+		return "udp6", "::"
+	case privileged:
+		return "ip4:icmp", "0.0.0.0"
+	default:
+		return "udp4", "0.0.0.0"
+	}
+}
+
+// setEchoSocketOptions は送信TTLを設定し、応答のTTLを受け取れるようにします。応答のTTLが取れない環境では0のままにします。
+func setEchoSocketOptions(conn *icmp.PacketConn, v6 bool, ttl int) error {
+	if v6 {
+		pc := conn.IPv6PacketConn()
+		pc.SetControlMessage(ipv6.FlagHopLimit, true)
+		if ttl > 0 {
+			if err := pc.SetHopLimit(ttl); err != nil {
+				return fmt.Errorf("ホップリミットを設定できません: %w", err)
+			}
+		}
+		return nil
+	}
+	pc := conn.IPv4PacketConn()
+	pc.SetControlMessage(ipv4.FlagTTL, true)
+	if ttl > 0 {
+		if err := pc.SetTTL(ttl); err != nil {
+			return fmt.Errorf("TTLを設定できません: %w", err)
+		}
+	}
+	return nil
+}
+
+// readEcho は ICMP メッセージを1つ読み、その大きさと受信時のTTLを返します。
+func readEcho(conn *icmp.PacketConn, v6 bool, buf []byte) (int, int, error) {
+	if v6 {
+		n, cm, _, err := conn.IPv6PacketConn().ReadFrom(buf)
+		if cm != nil {
+			return n, cm.HopLimit, err
+		}
+		return n, 0, err
+	}
+	n, cm, _, err := conn.IPv4PacketConn().ReadFrom(buf)
+	if cm != nil {
+		return n, cm.TTL, err
+	}
+	return n, 0, err
+}
+
+// matchEchoReply は b が id と seq の Echo への応答で、送ったペイロードがそのまま返ってきたものかどうかを返します。
+func matchEchoReply(b []byte, v6 bool, id, seq int, checkID bool, payload []byte) bool {
+	proto := protocolICMP
+	if v6 {
+		proto = protocolICMPv6
+	}
+	msg, err := icmp.ParseMessage(proto, b)
+	if err != nil || (msg.Type != ipv4.ICMPTypeEchoReply && msg.Type != ipv6.ICMPTypeEchoReply) {
+		return false
+	}
+	echo, ok := msg.Body.(*icmp.Echo)
+	if !ok {
+		return false
+	}
+	return echo.Seq == seq&0xffff && (!checkID || echo.ID == id) && bytes.Equal(echo.Data, payload)
+}
@@ -0,0 +1,83 @@
+package ping
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"nyagoPing/internal/domain/model"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+)
+
+func TestEchoPayload(t *testing.T) {
+	if got := echoPayload(5, []byte{0xff, 0x00}); !bytes.Equal(got, []byte{0xff, 0x00, 0xff, 0x00, 0xff}) {
+		t.Errorf("echoPayload() = %x", got)
+	}
+	if got := echoPayload(0, nil); len(got) != defaultEchoSize {
+		t.Errorf("echoPayload() の既定の長さ = %d, want %d", len(got), defaultEchoSize)
+	}
+}
+
+func TestMatchEchoReply(t *testing.T) {
+	payload := echoPayload(8, []byte{0xab})
+	reply := func(typ ipv4.ICMPType, id, seq int, data []byte) []byte {
+		b, _ := (&icmp.Message{Type: typ, Body: &icmp.Echo{ID: id, Seq: seq, Data: data}}).Marshal(nil)
+		return b
+	}
+
+	tests := []struct {
+		name    string
+		b       []byte
+		checkID bool
+		want    bool
+	}{
+		{"一致", reply(ipv4.ICMPTypeEchoReply, 7, 3, payload), true, true},
+		{"要求は応答ではない", reply(ipv4.ICMPTypeEcho, 7, 3, payload), true, false},
+		{"シーケンス違い", reply(ipv4.ICMPTypeEchoReply, 7, 4, payload), true, false},
+		{"ID違い", reply(ipv4.ICMPTypeEchoReply, 8, 3, payload), true, false},
+		{"非特権ではIDを見ない", reply(ipv4.ICMPTypeEchoReply, 8, 3, payload), false, true},
+		{"ペイロード違い", reply(ipv4.ICMPTypeEchoReply, 7, 3, echoPayload(8, []byte{0x01})), true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchEchoReply(tt.b, false, 7, 3, tt.checkID, payload); got != tt.want {
+				t.Errorf("matchEchoReply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProBingRepository_Ping_Pattern(t *testing.T) {
+	// 非特権のICMPソケットが使えなければ raw ソケットを試す
+	privileged := false
+	conn, err := icmp.ListenPacket("udp4", "127.0.0.1")
+	if err != nil {
+		privileged = true
+		conn, err = icmp.ListenPacket("ip4:icmp", "127.0.0.1")
+	}
+	if err != nil {
+		t.Skipf("ICMPソケットを開けない環境です: %v", err)
+	}
+	conn.Close()
+
+	config, err := model.NewPingConfig(2, privileged, 10*time.Millisecond, time.Second, 0)
+	if err != nil {
+		t.Fatalf("NewPingConfig() error = %v", err)
+	}
+	if err := config.SetSize(16); err != nil {
+		t.Fatalf("SetSize() error = %v", err)
+	}
+	if err := config.SetPattern([]byte{0xff, 0x00}); err != nil {
+		t.Fatalf("SetPattern() error = %v", err)
+	}
+	packets, events, _ := collectPing(t, NewProBingRepository(), "127.0.0.1", config)
+	if len(packets) != 2 || len(events) != 0 {
+		t.Fatalf("受信 %d 件, イベント %v, want 受信2件", len(packets), events)
+	}
+	// ICMPヘッダー8バイトとパターンで埋めた16バイト
+	if packets[0].Nbytes != 24 || packets[0].TTL == 0 || packets[1].ArtLine != "line2" {
+		t.Errorf("packets = %+v, %+v", packets[0], packets[1])
+	}
+}
@@ -0,0 +1,193 @@
+package ping
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"nyagoPing/internal/domain/model"
+	"nyagoPing/internal/domain/repository"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+const (
+	protocolICMP   = 1
+	protocolICMPv6 = 58
+	// ipv6HeaderSize はエラー通知に引用される IPv6 ヘッダーの大きさです。拡張ヘッダーは扱いません。
+	ipv6HeaderSize = 40
+)
+
+var tracePayload = []byte("nyagoping trace")
+
+// ICMPTraceRepository は TTL を制限した ICMP Echo を送り、Time Exceeded を返したルーターを調べます。
+// ルーターからのエラー通知を受け取るため raw ソケットを使い、管理者権限が必要です。
+type ICMPTraceRepository struct {
+	id  int
+	seq atomic.Uint32
+}
+
+func NewICMPTraceRepository() repository.TraceRepository {
+	return &ICMPTraceRepository{id: os.Getpid() & 0xffff}
+}
+
+func (r *ICMPTraceRepository) Resolve(ctx context.Context, target *model.PingTarget, config *model.TraceConfig) error {
+	return resolveTraceTarget(ctx, target, config.Family())
+}
+
+func (r *ICMPTraceRepository) ProbeHop(ctx context.Context, target *model.PingTarget, config *model.TraceConfig, ttl int) (*model.TraceReply, error) {
+	v6 := target.Family() == model.IPFamilyV6
+	network, address := "ip4:icmp", "0.0.0.0"
+	if v6 {
+		network, address = "ip6:ipv6-icmp", "::"
+	}
+	conn, err := icmp.ListenPacket(network, address)
+	if err != nil {
+		return nil, fmt.Errorf("ICMPソケットを開けません。trace には管理者権限が必要です: %w", err)
+	}
+	defer conn.Close()
+
+	seq := int(r.seq.Add(1) & 0xffff)
+	msg := icmp.Message{
+		Body: &icmp.Echo{ID: r.id, Seq: seq, Data: tracePayload},
+	}
+	if v6 {
+		msg.Type = ipv6.ICMPTypeEchoRequest
+		err = conn.IPv6PacketConn().SetHopLimit(ttl)
+	} else {
+		msg.Type = ipv4.ICMPTypeEcho
+		err = conn.IPv4PacketConn().SetTTL(ttl)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("TTLを設定できません: %w", err)
+	}
+	b, err := msg.Marshal(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(config.Timeout())
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetReadDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetReadDeadline(time.Now())
+	})
+	defer stop()
+
+	start := time.Now()
+	if _, err := conn.WriteTo(b, &net.IPAddr{IP: target.IP()}); err != nil {
+		return nil, fmt.Errorf("送信エラー: %w", err)
+	}
+
+	buf := make([]byte, 1500)
+	for {
+		n, peer, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return &model.TraceReply{}, nil
+			}
+			return nil, fmt.Errorf("受信エラー: %w", err)
+		}
+		rtt := time.Since(start)
+
+		reply, ok := r.matchReply(buf[:n], v6, seq)
+		if !ok {
+			// 他のプロセスや前のプローブ宛ての ICMP も届くため、読み続ける
+			continue
+		}
+		reply.Addr = peer.(*net.IPAddr).IP
+		reply.Rtt = rtt
+		return reply, nil
+	}
+}
+
+// matchReply は受信した ICMP メッセージが seq のプローブへの応答であれば、その種類を返します。
+func (r *ICMPTraceRepository) matchReply(b []byte, v6 bool, seq int) (*model.TraceReply, bool) {
+	proto := protocolICMP
+	if v6 {
+		proto = protocolICMPv6
+	}
+	msg, err := icmp.ParseMessage(proto, b)
+	if err != nil {
+		return nil, false
+	}
+
+	switch body := msg.Body.(type) {
+	case *icmp.Echo:
+		if msg.Type != ipv4.ICMPTypeEchoReply && msg.Type != ipv6.ICMPTypeEchoReply {
+			return nil, false
+		}
+		return &model.TraceReply{Reached: true}, body.ID == r.id && body.Seq == seq
+	case *icmp.TimeExceeded:
+		return &model.TraceReply{}, r.matchQuoted(body.Data, v6, seq)
+	case *icmp.DstUnreach:
+		return &model.TraceReply{Unreachable: true}, r.matchQuoted(body.Data, v6, seq)
+	default:
+		return nil, false
+	}
+}
+
+// matchQuoted はエラー通知に引用された元のパケットが seq のプローブかどうかを返します。
+func (r *ICMPTraceRepository) matchQuoted(data []byte, v6 bool, seq int) bool {
+	headerSize := ipv6HeaderSize
+	if !v6 {
+		if len(data) == 0 {
+			return false
+		}
+		headerSize = int(data[0]&0x0f) * 4
+	}
+	if len(data) < headerSize+8 {
+		return false
+	}
+	echo := data[headerSize:]
+	id := int(binary.BigEndian.Uint16(echo[4:6]))
+	quotedSeq := int(binary.BigEndian.Uint16(echo[6:8]))
+	return id == r.id && quotedSeq == seq
+}
+
+func (r *ICMPTraceRepository) LookupName(ctx context.Context, addr net.IP) string {
+	return lookupName(ctx, addr)
+}
+
+// resolveTraceTarget はホスト名を family のアドレスに解決して target に設定します。
+func resolveTraceTarget(ctx context.Context, target *model.PingTarget, family model.IPFamily) error {
+	ips, err := net.DefaultResolver.LookupIP(ctx, family.Network(), target.Host())
+	if err != nil || len(ips) == 0 {
+		if err == nil {
+			err = fmt.Errorf("アドレスがありません")
+		}
+		return resolveError(target, family, err)
+	}
+	// どちらのファミリーでもよい場合は ping と同じく IPv4 を優先する
+	ip := ips[0]
+	for _, candidate := range ips {
+		if candidate.To4() != nil {
+			ip = candidate
+			break
+		}
+	}
+	target.SetIP(ip)
+	return nil
+}
+
+// lookupName は addr の逆引き名を末尾のドットを除いて返します。
+func lookupName(ctx context.Context, addr net.IP) string {
+	names, err := net.DefaultResolver.LookupAddr(ctx, addr.String())
+	if err != nil || len(names) == 0 {
+		return ""
+	}
+	return strings.TrimSuffix(names[0], ".")
+}
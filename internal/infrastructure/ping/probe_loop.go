@@ -0,0 +1,165 @@
+package ping
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"nyagoPing/internal/domain/model"
+	"time"
+)
+
+type probeResult struct {
+	nbytes     int
+	ttl        int
+	rtt        time.Duration
+	statusCode int
+	duplicates int
+}
+
+// errNoReply は応答が返ってこなかったことを表します。タイムアウトとして通知されます。
+var errNoReply = errors.New("応答がありません")
+
+// statusError は応答は得られたもののステータスコードが失敗を示していたことを表します。ロスとして通知され、コードはイベントに残ります。
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("ステータスコード %d", e.code)
+}
+
+// probeFunc は1回分の計測を行います。応答が得られなかった場合はエラーを返します。
+type probeFunc func(ctx context.Context, seq int) (*probeResult, error)
+
+// runProbes は ICMP 以外のリポジトリが共通で使う送信ループです。
+// config の送信間隔・回数・制限時間に従って probe を呼び出し、結果をアート付きのパケットとして通知します。
+func runProbes(
+	ctx context.Context,
+	target *model.PingTarget,
+	config *model.PingConfig,
+	art *model.ASCIIArt,
+	probe probeFunc,
+	onRecv func(*model.PingPacket),
+	onEvent func(*model.PingEvent),
+	onFinish func(*model.PingStatistics),
+) {
+	// 呼び出し側による中断と、制限時間による打ち切りを区別する
+	parent := ctx
+	if runTimeout := config.RunTimeout(); runTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, runTimeout)
+		defer cancel()
+	}
+
+	ticker := time.NewTicker(config.Interval())
+	defer ticker.Stop()
+
+	sent := 0
+	duplicates := 0
+	var rtts []time.Duration
+	for seq := 0; config.Count() == 0 || seq < config.Count(); seq++ {
+		if seq > 0 {
+			select {
+			case <-ctx.Done():
+			case <-ticker.C:
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		probeCtx := ctx
+		var probeCancel context.CancelFunc = func() {}
+		if config.Timeout() > 0 {
+			probeCtx, probeCancel = context.WithTimeout(ctx, config.Timeout())
+		}
+		result, err := probe(probeCtx, seq)
+		probeCancel()
+		if err != nil && parent.Err() != nil {
+			break
+		}
+		sent++
+		if err != nil {
+			event := model.NewPingEvent(probeEventType(err), seq, art.GetLineBySeq(seq), err)
+			event.ArtColors = art.GetColorsBySeq(seq)
+			event.ArtBackgrounds = art.GetBackgroundsBySeq(seq)
+			var statusErr *statusError
+			if errors.As(err, &statusErr) {
+				event.StatusCode = statusErr.code
+			}
+			onEvent(event)
+		} else {
+			rtts = append(rtts, result.rtt)
+			packet := model.NewPingPacket(
+				seq,
+				result.nbytes,
+				result.ttl,
+				target.IP(),
+				result.rtt,
+				art.GetLineBySeq(seq),
+			)
+			packet.ArtColors = art.GetColorsBySeq(seq)
+			packet.ArtBackgrounds = art.GetBackgroundsBySeq(seq)
+			packet.StatusCode = result.statusCode
+			onRecv(packet)
+
+			duplicates += result.duplicates
+			for i := 0; i < result.duplicates; i++ {
+				event := model.NewPingEvent(model.PingEventDuplicate, seq, packet.ArtLine, nil)
+				event.Rtt = result.rtt
+				onEvent(event)
+			}
+		}
+
+		if seq >= art.LineCount()-1 {
+			break
+		}
+	}
+
+	stats := model.CalculatePingStatistics(target.Host(), sent, rtts)
+	stats.PacketsDuplicates = duplicates
+	onFinish(stats)
+}
+
+// probeEventType は probe のエラーをタイムアウトかそれ以外のロスかに振り分けます。
+func probeEventType(err error) model.PingEventType {
+	if errors.Is(err, errNoReply) || errors.Is(err, context.DeadlineExceeded) {
+		return model.PingEventTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return model.PingEventTimeout
+	}
+	return model.PingEventLost
+}
+
+func resolveTarget(target *model.PingTarget, family model.IPFamily) error {
+	ipAddr, err := net.ResolveIPAddr(family.Network(), target.Host())
+	if err != nil {
+		return resolveError(target, family, err)
+	}
+	target.SetIP(ipAddr.IP)
+	return nil
+}
+
+// dialNetwork は "tcp" や "udp" にアドレスファミリーを反映した net.Dial 用のネットワーク名を返します。
+func dialNetwork(network string, family model.IPFamily) string {
+	switch family {
+	case model.IPFamilyV4:
+		return network + "4"
+	case model.IPFamilyV6:
+		return network + "6"
+	default:
+		return network
+	}
+}
+
+func resolveError(target *model.PingTarget, family model.IPFamily, err error) error {
+	if family != model.IPFamilyAny {
+		if _, anyErr := net.ResolveIPAddr(model.IPFamilyAny.Network(), target.Host()); anyErr == nil {
+			return fmt.Errorf("%s に%sアドレスがありません: %w", target.Host(), family, err)
+		}
+	}
+	return fmt.Errorf("Pingerの初期化エラー: %w", err)
+}
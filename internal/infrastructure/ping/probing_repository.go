@@ -1,16 +1,21 @@
 package ping
 
 import (
+	"context"
 	"fmt"
 	"nyagoPing/internal/domain/model"
 	"nyagoPing/internal/domain/repository"
-	"os"
-	"os/signal"
 	"runtime"
+	"sort"
+	"sync"
+	"time"
 
 	probing "github.com/prometheus-community/pro-bing"
 )
 
+// pro-bing はペイロード先頭にタイムスタンプ(8バイト)とトラッカーUUID(16バイト)を埋め込む
+const minProBingSize = 24
+
 type ProBingRepository struct{}
 
 func NewProBingRepository() repository.PingRepository {
@@ -18,31 +23,89 @@ func NewProBingRepository() repository.PingRepository {
 }
 
 func (r *ProBingRepository) Ping(
+	ctx context.Context,
 	target *model.PingTarget,
 	config *model.PingConfig,
 	art *model.ASCIIArt,
 	onRecv func(*model.PingPacket),
+	onEvent func(*model.PingEvent),
 	onFinish func(*model.PingStatistics),
 ) error {
-	pinger, err := probing.NewPinger(target.Host())
-	if err != nil {
-		return fmt.Errorf("Pingerの初期化エラー: %w", err)
+	if len(config.Pattern()) > 0 {
+		return pingWithPattern(ctx, target, config, art, onRecv, onEvent, onFinish)
+	}
+
+	pinger := probing.New(target.Host())
+	pinger.SetNetwork(config.Family().Network())
+	if err := pinger.Resolve(); err != nil {
+		return resolveError(target, config.Family(), err)
 	}
 
 	pinger.Count = config.Count()
+	pinger.Interval = config.Interval()
+	if runTimeout := config.RunTimeout(); runTimeout > 0 {
+		pinger.Timeout = runTimeout
+	}
+	if config.Size() > 0 {
+		if config.Size() < minProBingSize {
+			return fmt.Errorf("ICMPのペイロードサイズは%dバイト以上である必要があります: %d", minProBingSize, config.Size())
+		}
+		pinger.Size = config.Size()
+	}
+	if config.TTL() > 0 {
+		pinger.TTL = config.TTL()
+	}
+	target.SetIP(pinger.IPAddr().IP)
+
+	// pro-bing のコールバックと、応答の無いまま Timeout を過ぎたパケットを調べるティッカーは別のゴルーチンで動くため、mu で順に実行する。
+	// finished の後はティッカーから通知しない
+	var mu sync.Mutex
+	sentAt := make(map[int]time.Time)
+	var rtts []time.Duration
+	finished := false
+	expire := func(now time.Time, all bool) {
+		if finished {
+			return
+		}
+		for _, seq := range sortedSeqs(sentAt) {
+			if !all && (config.Timeout() == 0 || now.Sub(sentAt[seq]) < config.Timeout()) {
+				continue
+			}
+			delete(sentAt, seq)
+			event := model.NewPingEvent(model.PingEventTimeout, seq, art.GetLineBySeq(seq), errNoReply)
+			event.ArtColors = art.GetColorsBySeq(seq)
+			event.ArtBackgrounds = art.GetBackgroundsBySeq(seq)
+			onEvent(event)
+			if seq >= art.LineCount()-1 {
+				pinger.Stop()
+			}
+		}
+	}
 
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt)
-	go func() {
-		<-c
-		pinger.Stop()
-	}()
+	pinger.OnSend = func(pkt *probing.Packet) {
+		mu.Lock()
+		defer mu.Unlock()
+		now := time.Now()
+		expire(now, false)
+		sentAt[pkt.Seq] = now
+	}
 
-	if pinger.IPAddr() != nil {
-		target.SetIP(pinger.IPAddr().IP)
+	pinger.OnSendError = func(pkt *probing.Packet, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		onEvent(model.NewPingEvent(model.PingEventSendError, pkt.Seq, art.GetLineBySeq(pkt.Seq), err))
 	}
 
 	pinger.OnRecv = func(pkt *probing.Packet) {
+		mu.Lock()
+		defer mu.Unlock()
+		// タイムアウトを通知済みのシーケンスに遅れて届いた応答は数えない
+		if _, ok := sentAt[pkt.Seq]; !ok {
+			return
+		}
+		delete(sentAt, pkt.Seq)
+		rtts = append(rtts, pkt.Rtt)
+
 		artLine := art.GetLineBySeq(pkt.Seq)
 		packet := model.NewPingPacket(
 			pkt.Seq,
@@ -52,24 +115,32 @@ func (r *ProBingRepository) Ping(
 			pkt.Rtt,
 			artLine,
 		)
+		packet.ArtColors = art.GetColorsBySeq(pkt.Seq)
+		packet.ArtBackgrounds = art.GetBackgroundsBySeq(pkt.Seq)
 		onRecv(packet)
-		
+
 		if pkt.Seq >= art.LineCount()-1 {
 			pinger.Stop()
 		}
 	}
 
+	pinger.OnDuplicateRecv = func(pkt *probing.Packet) {
+		mu.Lock()
+		defer mu.Unlock()
+		event := model.NewPingEvent(model.PingEventDuplicate, pkt.Seq, art.GetLineBySeq(pkt.Seq), nil)
+		event.Rtt = pkt.Rtt
+		onEvent(event)
+	}
+
 	pinger.OnFinish = func(stats *probing.Statistics) {
-		statistics := model.NewPingStatistics(
-			stats.Addr,
-			stats.PacketsSent,
-			stats.PacketsRecv,
-			stats.PacketLoss,
-			stats.MinRtt,
-			stats.AvgRtt,
-			stats.MaxRtt,
-			stats.StdDevRtt,
-		)
+		mu.Lock()
+		defer mu.Unlock()
+		// 中断された場合は送ったばかりのパケットをタイムアウト扱いにしない
+		expire(time.Now(), ctx.Err() == nil)
+		finished = true
+
+		statistics := model.CalculatePingStatistics(stats.Addr, stats.PacketsSent, rtts)
+		statistics.PacketsDuplicates = stats.PacketsRecvDuplicates
 		onFinish(statistics)
 	}
 
@@ -77,9 +148,39 @@ func (r *ProBingRepository) Ping(
 		pinger.SetPrivileged(true)
 	}
 
-	if err := pinger.Run(); err != nil {
+	if timeout := config.Timeout(); timeout > 0 {
+		ticker := time.NewTicker(timeout)
+		done := make(chan struct{})
+		defer func() {
+			ticker.Stop()
+			close(done)
+		}()
+		go func() {
+			for {
+				select {
+				case <-done:
+					return
+				case now := <-ticker.C:
+					mu.Lock()
+					expire(now, false)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	if err := pinger.RunWithContext(ctx); err != nil && ctx.Err() == nil {
 		return fmt.Errorf("Ping実行エラー: %w", err)
 	}
 
 	return nil
 }
+
+func sortedSeqs(sentAt map[int]time.Time) []int {
+	seqs := make([]int, 0, len(sentAt))
+	for seq := range sentAt {
+		seqs = append(seqs, seq)
+	}
+	sort.Ints(seqs)
+	return seqs
+}
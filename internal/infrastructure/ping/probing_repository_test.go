@@ -0,0 +1,72 @@
+package ping
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"nyagoPing/internal/domain/model"
+
+	"golang.org/x/net/icmp"
+)
+
+func TestProBingRepository_Ping_TimeoutBetweenSends(t *testing.T) {
+	// 非特権のICMPソケットが使えなければ raw ソケットを試す
+	privileged := false
+	conn, err := icmp.ListenPacket("udp4", "0.0.0.0")
+	if err != nil {
+		privileged = true
+		conn, err = icmp.ListenPacket("ip4:icmp", "0.0.0.0")
+	}
+	if err != nil {
+		t.Skipf("ICMPソケットを開けない環境です: %v", err)
+	}
+	conn.Close()
+
+	// 応答の返らない TEST-NET-2 に、次の送信より十分短いタイムアウトで送る
+	config, err := model.NewPingConfig(2, privileged, 5*time.Second, 100*time.Millisecond, 0)
+	if err != nil {
+		t.Fatalf("NewPingConfig() error = %v", err)
+	}
+	target, _ := model.NewPingTarget("198.51.100.1")
+	art, _ := model.NewASCIIArt([]string{"line1", "line2"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	start := time.Now()
+	var first *model.PingEvent
+	var elapsed time.Duration
+	received := false
+	err = NewProBingRepository().Ping(ctx, target, config, art,
+		func(*model.PingPacket) {
+			received = true
+			cancel()
+		},
+		func(event *model.PingEvent) {
+			if first == nil {
+				first, elapsed = event, time.Since(start)
+				cancel()
+			}
+		},
+		func(*model.PingStatistics) {},
+	)
+	if err != nil {
+		t.Skipf("送信できない環境です: %v", err)
+	}
+	if received {
+		t.Skip("TEST-NET-2 から応答が返る環境です")
+	}
+	if first == nil {
+		t.Fatal("タイムアウトが通知されませんでした")
+	}
+	if first.Type == model.PingEventSendError {
+		t.Skipf("送信できない環境です: %v", first.Err)
+	}
+	if first.Type != model.PingEventTimeout || first.Seq != 0 {
+		t.Fatalf("最初のイベント = %+v, want seq 0 のタイムアウト", first)
+	}
+	// 次の送信(5秒後)を待たずに通知される
+	if elapsed >= time.Second {
+		t.Errorf("タイムアウトの通知まで %v かかりました", elapsed)
+	}
+}
@@ -0,0 +1,35 @@
+package ping
+
+import (
+	"context"
+	"fmt"
+	"nyagoPing/internal/domain/model"
+	"nyagoPing/internal/domain/repository"
+)
+
+// ProtocolRepository は PingConfig のプロトコルに応じて実際のリポジトリへ処理を振り分けます。
+type ProtocolRepository struct {
+	repos map[model.Protocol]repository.PingRepository
+}
+
+func NewProtocolRepository(repos map[model.Protocol]repository.PingRepository) repository.PingRepository {
+	return &ProtocolRepository{
+		repos: repos,
+	}
+}
+
+func (r *ProtocolRepository) Ping(
+	ctx context.Context,
+	target *model.PingTarget,
+	config *model.PingConfig,
+	art *model.ASCIIArt,
+	onRecv func(*model.PingPacket),
+	onEvent func(*model.PingEvent),
+	onFinish func(*model.PingStatistics),
+) error {
+	repo, ok := r.repos[config.Protocol()]
+	if !ok {
+		return fmt.Errorf("%s には対応していません", config.Protocol())
+	}
+	return repo.Ping(ctx, target, config, art, onRecv, onEvent, onFinish)
+}
@@ -0,0 +1,119 @@
+package ping
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"nyagoPing/internal/domain/model"
+	"nyagoPing/internal/domain/repository"
+	"time"
+)
+
+const (
+	simulatedICMPHeaderSize = 8
+	simulatedDefaultSize    = 56
+	simulatedDefaultTTL     = 64
+	simulatedMinRtt         = time.Microsecond
+)
+
+var (
+	simulatedIPv4 = net.ParseIP("192.0.2.1")
+	simulatedIPv6 = net.ParseIP("2001:db8::1")
+)
+
+// SimulatedRepository はネットワークを使わず、PingConfig の筋書きに従って応答を再現します。
+// 同じシードからは常に同じRTT・ロス・重複の並びが得られます。同じ筋書きで繰り返し Ping すると乱数は前回の続きから引きます。
+type SimulatedRepository struct{}
+
+func NewSimulatedRepository() repository.PingRepository {
+	return &SimulatedRepository{}
+}
+
+func (r *SimulatedRepository) Ping(
+	ctx context.Context,
+	target *model.PingTarget,
+	config *model.PingConfig,
+	art *model.ASCIIArt,
+	onRecv func(*model.PingPacket),
+	onEvent func(*model.PingEvent),
+	onFinish func(*model.PingStatistics),
+) error {
+	scenario := config.Scenario()
+	if scenario == nil {
+		return fmt.Errorf("シミュレーションの筋書きが指定されていません")
+	}
+
+	target.SetIP(simulatedIP(target.Host(), config.Family()))
+
+	rnd := scenario.Rand()
+
+	size := config.Size()
+	if size == 0 {
+		size = simulatedDefaultSize
+	}
+	ttl := config.TTL()
+	if ttl == 0 {
+		ttl = simulatedDefaultTTL
+	}
+
+	runProbes(ctx, target, config, art, func(ctx context.Context, seq int) (*probeResult, error) {
+		// 分岐に関わらず毎回同じ回数だけ乱数を引き、シードごとの結果を安定させる
+		rtt := sampleRtt(rnd, scenario)
+		lost := rnd.Float64() < scenario.LossRate() || scenario.InLossBurst(seq)
+		duplicated := rnd.Float64() < scenario.DuplicateRate()
+
+		if lost {
+			return nil, errNoReply
+		}
+
+		timer := time.NewTimer(rtt)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		result := &probeResult{
+			nbytes: simulatedICMPHeaderSize + size,
+			ttl:    ttl,
+			rtt:    rtt,
+		}
+		if duplicated {
+			result.duplicates = 1
+		}
+		return result, nil
+	}, onRecv, onEvent, onFinish)
+
+	return nil
+}
+
+func sampleRtt(rnd *rand.Rand, scenario *model.SimulationScenario) time.Duration {
+	mean := float64(scenario.RttMean())
+	jitter := float64(scenario.RttJitter())
+
+	var rtt float64
+	switch scenario.Distribution() {
+	case model.RttDistributionUniform:
+		rtt = mean + jitter*(2*rnd.Float64()-1)
+	default:
+		rtt = mean + jitter*rnd.NormFloat64()
+	}
+
+	if rtt < float64(simulatedMinRtt) {
+		return simulatedMinRtt
+	}
+	return time.Duration(rtt)
+}
+
+// simulatedIP はホストがIPアドレスならそのまま使い、ホスト名なら文書用のアドレスを返します。
+func simulatedIP(host string, family model.IPFamily) net.IP {
+	if ip := net.ParseIP(host); ip != nil {
+		return ip
+	}
+	if family == model.IPFamilyV6 {
+		return simulatedIPv6
+	}
+	return simulatedIPv4
+}
@@ -0,0 +1,96 @@
+package ping
+
+import (
+	"testing"
+	"time"
+
+	"nyagoPing/internal/domain/model"
+)
+
+func TestSimulatedRepository_Ping_Deterministic(t *testing.T) {
+	// 同じ筋書きを使い回すと乱数は続きから引くため、同じシードの筋書きを作り直す
+	run := func() ([]*model.PingPacket, *model.PingStatistics) {
+		scenario, _ := model.NewSimulationScenario(42, model.RttDistributionNormal, time.Millisecond, 200*time.Microsecond, 0.2, nil, 0.1)
+		config := newProtocolTestConfig(t, model.ProtocolICMP, 0, 20, 0)
+		if err := config.SetSimulation(scenario); err != nil {
+			t.Fatalf("SetSimulation() error = %v", err)
+		}
+		packets, _, stats := collectPing(t, NewSimulatedRepository(), "example.tld", config)
+		return packets, stats
+	}
+	first, firstStats := run()
+	second, secondStats := run()
+
+	if len(first) != len(second) {
+		t.Fatalf("同じシードで受信数が異なります: %d != %d", len(first), len(second))
+	}
+	for i := range first {
+		if first[i].Seq != second[i].Seq || first[i].Rtt != second[i].Rtt {
+			t.Errorf("packet[%d] = (%d, %v), want (%d, %v)", i, second[i].Seq, second[i].Rtt, first[i].Seq, first[i].Rtt)
+		}
+	}
+	if firstStats.PacketsDuplicates != secondStats.PacketsDuplicates {
+		t.Errorf("重複数が異なります: %d != %d", firstStats.PacketsDuplicates, secondStats.PacketsDuplicates)
+	}
+	if firstStats.PacketsSent != 20 {
+		t.Errorf("PacketsSent = %d, want 20", firstStats.PacketsSent)
+	}
+}
+
+func TestSimulatedRepository_Ping_LossBurst(t *testing.T) {
+	scenario, _ := model.NewSimulationScenario(1, model.RttDistributionUniform, time.Millisecond, 0, 0, []model.LossBurst{{Start: 2, Length: 3}}, 0)
+
+	config := newProtocolTestConfig(t, model.ProtocolICMP, 0, 8, 0)
+	if err := config.SetSimulation(scenario); err != nil {
+		t.Fatalf("SetSimulation() error = %v", err)
+	}
+	packets, events, stats := collectPing(t, NewSimulatedRepository(), "example.tld", config)
+
+	var seqs []int
+	for _, packet := range packets {
+		seqs = append(seqs, packet.Seq)
+		if packet.Seq >= 2 && packet.Seq < 5 {
+			t.Errorf("ロスバースト中のシーケンス %d を受信しました", packet.Seq)
+		}
+		if packet.Rtt != time.Millisecond {
+			t.Errorf("packet[%d].Rtt = %v, want 1ms", packet.Seq, packet.Rtt)
+		}
+	}
+	if len(seqs) != 5 {
+		t.Errorf("受信したシーケンス = %v, want 5個", seqs)
+	}
+	if stats.PacketsSent != 8 || stats.PacketsRecv != 5 {
+		t.Errorf("stats = %+v, want 8送信 5受信", stats)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("イベント数 = %d, want 3", len(events))
+	}
+	for i, event := range events {
+		if event.Type != model.PingEventTimeout || event.Seq != 2+i {
+			t.Errorf("events[%d] = (%v, %d), want (timeout, %d)", i, event.Type, event.Seq, 2+i)
+		}
+	}
+}
+
+func TestSimulatedRepository_Ping_Duplicates(t *testing.T) {
+	scenario, _ := model.NewSimulationScenario(1, model.RttDistributionUniform, time.Millisecond, 0, 0, nil, 1)
+
+	config := newProtocolTestConfig(t, model.ProtocolICMP, 0, 3, 0)
+	if err := config.SetSimulation(scenario); err != nil {
+		t.Fatalf("SetSimulation() error = %v", err)
+	}
+	packets, events, stats := collectPing(t, NewSimulatedRepository(), "example.tld", config)
+
+	if len(events) != len(packets) {
+		t.Fatalf("イベント数 = %d, want %d", len(events), len(packets))
+	}
+	for i, event := range events {
+		if event.Type != model.PingEventDuplicate || event.Seq != packets[i].Seq || event.Rtt != time.Millisecond {
+			t.Errorf("events[%d] = %+v, want 重複 seq=%d", i, event, packets[i].Seq)
+		}
+	}
+	if stats.PacketsDuplicates != 3 {
+		t.Errorf("PacketsDuplicates = %d, want 3", stats.PacketsDuplicates)
+	}
+}
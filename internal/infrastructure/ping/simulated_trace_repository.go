@@ -0,0 +1,129 @@
+package ping
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"net"
+	"nyagoPing/internal/domain/model"
+	"nyagoPing/internal/domain/repository"
+	"sync"
+	"time"
+)
+
+const (
+	simulatedMinHops = 4
+	simulatedMaxHops = 12
+)
+
+var (
+	simulatedRouterIPv4 = net.ParseIP("198.51.100.0")
+	simulatedRouterIPv6 = net.ParseIP("2001:db8:1::")
+)
+
+// SimulatedTraceRepository はネットワークを使わず、TraceConfig の筋書きに従って経路を再現します。
+// 経路のホップ数はホスト名から決まり、各ルーターの RTT は宛先に近づくほど筋書きの平均に近づきます。
+type SimulatedTraceRepository struct {
+	mu   sync.Mutex
+	rnd  *rand.Rand
+	host string
+	hops int
+}
+
+func NewSimulatedTraceRepository() repository.TraceRepository {
+	return &SimulatedTraceRepository{}
+}
+
+func (r *SimulatedTraceRepository) Resolve(ctx context.Context, target *model.PingTarget, config *model.TraceConfig) error {
+	scenario := config.Scenario()
+	if scenario == nil {
+		return fmt.Errorf("シミュレーションの筋書きが指定されていません")
+	}
+	target.SetIP(simulatedIP(target.Host(), config.Family()))
+
+	seed := scenario.Seed()
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	h := fnv.New32a()
+	h.Write([]byte(target.Host()))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rnd = rand.New(rand.NewSource(seed))
+	r.host = target.Host()
+	r.hops = simulatedMinHops + int(h.Sum32()%(simulatedMaxHops-simulatedMinHops+1))
+	return nil
+}
+
+func (r *SimulatedTraceRepository) ProbeHop(ctx context.Context, target *model.PingTarget, config *model.TraceConfig, ttl int) (*model.TraceReply, error) {
+	scenario := config.Scenario()
+	if scenario == nil {
+		return nil, fmt.Errorf("シミュレーションの筋書きが指定されていません")
+	}
+
+	r.mu.Lock()
+	if r.rnd == nil {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("宛先が解決されていません")
+	}
+	rtt := sampleRtt(r.rnd, scenario)
+	lost := r.rnd.Float64() < scenario.LossRate()
+	hops := r.hops
+	r.mu.Unlock()
+
+	reached := ttl >= hops
+	if !reached {
+		rtt = max(rtt*time.Duration(ttl)/time.Duration(hops), simulatedMinRtt)
+	}
+	wait := min(rtt, config.Timeout())
+	if lost {
+		wait = config.Timeout()
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.C:
+	}
+
+	if lost || rtt > config.Timeout() {
+		return &model.TraceReply{}, nil
+	}
+	if reached {
+		return &model.TraceReply{Addr: target.IP(), Rtt: rtt, Reached: true}, nil
+	}
+	return &model.TraceReply{Addr: simulatedRouterIP(ttl, target.Family()), Rtt: rtt}, nil
+}
+
+func (r *SimulatedTraceRepository) LookupName(ctx context.Context, addr net.IP) string {
+	for _, family := range []model.IPFamily{model.IPFamilyV4, model.IPFamilyV6} {
+		for hop := 1; hop < simulatedMaxHops; hop++ {
+			if addr.Equal(simulatedRouterIP(hop, family)) {
+				return fmt.Sprintf("router%d.example.net", hop)
+			}
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if net.ParseIP(r.host) == nil {
+		return r.host
+	}
+	return ""
+}
+
+// simulatedRouterIP は hop 番目のルーターの文書用アドレスを返します。
+func simulatedRouterIP(hop int, family model.IPFamily) net.IP {
+	base := simulatedRouterIPv4
+	if family == model.IPFamilyV6 {
+		base = simulatedRouterIPv6
+	}
+	ip := make(net.IP, len(base))
+	copy(ip, base)
+	ip[len(ip)-1] = byte(hop)
+	return ip
+}
@@ -0,0 +1,62 @@
+package ping
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"nyagoPing/internal/domain/model"
+	"nyagoPing/internal/domain/repository"
+	"strconv"
+	"time"
+)
+
+// TCPRepository はTCPハンドシェイクにかかった時間をRTTとして計測します。
+// ICMPが遮断されているホストでもサービスの疎通を確認できます。TTLはSYNを送る前のソケットに設定します。
+type TCPRepository struct{}
+
+func NewTCPRepository() repository.PingRepository {
+	return &TCPRepository{}
+}
+
+func (r *TCPRepository) Ping(
+	ctx context.Context,
+	target *model.PingTarget,
+	config *model.PingConfig,
+	art *model.ASCIIArt,
+	onRecv func(*model.PingPacket),
+	onEvent func(*model.PingEvent),
+	onFinish func(*model.PingStatistics),
+) error {
+	if len(config.Pattern()) > 0 {
+		return fmt.Errorf("TCPモードではペイロードパターンを指定できません")
+	}
+	if config.Size() > 0 {
+		return fmt.Errorf("TCPモードではペイロードサイズを指定できません")
+	}
+	var dialer net.Dialer
+	if config.TTL() > 0 {
+		control, err := dialTTLControl(config.TTL())
+		if err != nil {
+			return err
+		}
+		dialer.Control = control
+	}
+	if err := resolveTarget(target, config.Family()); err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(target.IP().String(), strconv.Itoa(config.Port()))
+
+	runProbes(ctx, target, config, art, func(ctx context.Context, seq int) (*probeResult, error) {
+		start := time.Now()
+		conn, err := dialer.DialContext(ctx, dialNetwork("tcp", config.Family()), addr)
+		if err != nil {
+			return nil, err
+		}
+		rtt := time.Since(start)
+		conn.Close()
+		return &probeResult{rtt: rtt}, nil
+	}, onRecv, onEvent, onFinish)
+
+	return nil
+}
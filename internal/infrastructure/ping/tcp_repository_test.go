@@ -0,0 +1,142 @@
+package ping
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"nyagoPing/internal/domain/model"
+)
+
+func TestTCPRepository_Ping(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	config := newProtocolTestConfig(t, model.ProtocolTCP, listener.Addr().(*net.TCPAddr).Port, 3, 500*time.Millisecond)
+	packets, _, stats := collectPing(t, NewTCPRepository(), "127.0.0.1", config)
+
+	if len(packets) != 3 {
+		t.Fatalf("受信数 = %d, want 3", len(packets))
+	}
+	for i, packet := range packets {
+		if packet.Seq != i {
+			t.Errorf("packet[%d].Seq = %d", i, packet.Seq)
+		}
+		if want := fmt.Sprintf("line%d", i+1); packet.ArtLine != want {
+			t.Errorf("packet[%d].ArtLine = %q, want %q", i, packet.ArtLine, want)
+		}
+		if !packet.IPAddr.Equal(net.IPv4(127, 0, 0, 1)) {
+			t.Errorf("packet[%d].IPAddr = %v", i, packet.IPAddr)
+		}
+	}
+	if stats == nil || stats.PacketsSent != 3 || stats.PacketsRecv != 3 {
+		t.Errorf("stats = %+v, want 3送信 3受信", stats)
+	}
+}
+
+func TestTCPRepository_Ping_Refused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	packets, events, stats := collectPing(t, NewTCPRepository(), "127.0.0.1", newProtocolTestConfig(t, model.ProtocolTCP, port, 2, 500*time.Millisecond))
+	if len(packets) != 0 {
+		t.Errorf("接続できないポートで応答を受信しました: %+v", packets)
+	}
+	if stats == nil || stats.PacketsSent != 2 || stats.PacketLoss != 100 {
+		t.Errorf("stats = %+v, want 2送信 100%%ロス", stats)
+	}
+	for i, event := range events {
+		if event.Type != model.PingEventLost || event.Seq != i || event.ArtLine != fmt.Sprintf("line%d", i+1) {
+			t.Errorf("events[%d] = %+v, want seq %d のロス", i, event, i)
+		}
+	}
+}
+
+func TestTCPRepository_Ping_Cancel(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	repo := NewTCPRepository()
+	target, _ := model.NewPingTarget("127.0.0.1")
+	config := newProtocolTestConfig(t, model.ProtocolTCP, listener.Addr().(*net.TCPAddr).Port, 0, 500*time.Millisecond)
+	art, _ := model.NewASCIIArt(make([]string, 1000))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	var stats *model.PingStatistics
+	done := make(chan error, 1)
+	go func() {
+		done <- repo.Ping(ctx, target, config, art,
+			func(*model.PingPacket) {},
+			func(*model.PingEvent) {},
+			func(s *model.PingStatistics) {
+				stats = s
+			},
+		)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Ping() error = %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("キャンセル後もPingが終了しません")
+	}
+	if stats == nil || stats.PacketsSent == 0 {
+		t.Errorf("stats = %+v, want キャンセルまでの統計", stats)
+	}
+}
+
+func TestTCPRepository_Ping_Unsupported(t *testing.T) {
+	repo := NewTCPRepository()
+	target, _ := model.NewPingTarget("127.0.0.1")
+	art, _ := model.NewASCIIArt([]string{"line1"})
+
+	sized := newProtocolTestConfig(t, model.ProtocolTCP, 80, 1, 500*time.Millisecond)
+	if err := sized.SetSize(32); err != nil {
+		t.Fatalf("SetSize() error = %v", err)
+	}
+	patterned := newProtocolTestConfig(t, model.ProtocolTCP, 80, 1, 500*time.Millisecond)
+	if err := patterned.SetPattern([]byte{0xff}); err != nil {
+		t.Fatalf("SetPattern() error = %v", err)
+	}
+
+	for name, config := range map[string]*model.PingConfig{"サイズ": sized, "パターン": patterned} {
+		err := repo.Ping(context.Background(), target, config, art, func(*model.PingPacket) {}, func(*model.PingEvent) {}, func(*model.PingStatistics) {})
+		if err == nil {
+			t.Errorf("%s を指定してもエラーになりませんでした", name)
+		}
+	}
+}
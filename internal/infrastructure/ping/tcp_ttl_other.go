@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+
+package ping
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// dialTTLControl はこの環境ではソケットのTTLを設定できないため、エラーを返します。
+func dialTTLControl(ttl int) (func(network, address string, c syscall.RawConn) error, error) {
+	return nil, fmt.Errorf("この環境ではTCPモードのTTLを指定できません")
+}
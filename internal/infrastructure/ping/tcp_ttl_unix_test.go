@@ -0,0 +1,45 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package ping
+
+import (
+	"net"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestDialTTLControl(t *testing.T) {
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer listener.Close()
+
+	control, err := dialTTLControl(7)
+	if err != nil {
+		t.Fatalf("dialTTLControl() error = %v", err)
+	}
+	dialer := net.Dialer{Control: control}
+	conn, err := dialer.Dial("tcp4", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+
+	raw, err := conn.(*net.TCPConn).SyscallConn()
+	if err != nil {
+		t.Fatalf("SyscallConn() error = %v", err)
+	}
+	var ttl int
+	var sockErr error
+	raw.Control(func(fd uintptr) {
+		ttl, sockErr = unix.GetsockoptInt(int(fd), unix.IPPROTO_IP, unix.IP_TTL)
+	})
+	if sockErr != nil {
+		t.Fatalf("GetsockoptInt() error = %v", sockErr)
+	}
+	if ttl != 7 {
+		t.Errorf("TTL = %d, want 7", ttl)
+	}
+}
@@ -0,0 +1,54 @@
+package ping
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"nyagoPing/internal/domain/model"
+	"nyagoPing/internal/domain/repository"
+)
+
+// TraceProtocolRepository は TraceConfig のプロトコルに応じて実際のリポジトリへ処理を振り分けます。
+type TraceProtocolRepository struct {
+	repos map[model.Protocol]repository.TraceRepository
+	// resolved は LookupName を Resolve したリポジトリに振り分けるために覚えておきます
+	resolved repository.TraceRepository
+}
+
+func NewTraceProtocolRepository(repos map[model.Protocol]repository.TraceRepository) repository.TraceRepository {
+	return &TraceProtocolRepository{
+		repos: repos,
+	}
+}
+
+func (r *TraceProtocolRepository) Resolve(ctx context.Context, target *model.PingTarget, config *model.TraceConfig) error {
+	repo, err := r.repo(config)
+	if err != nil {
+		return err
+	}
+	r.resolved = repo
+	return repo.Resolve(ctx, target, config)
+}
+
+func (r *TraceProtocolRepository) ProbeHop(ctx context.Context, target *model.PingTarget, config *model.TraceConfig, ttl int) (*model.TraceReply, error) {
+	repo, err := r.repo(config)
+	if err != nil {
+		return nil, err
+	}
+	return repo.ProbeHop(ctx, target, config, ttl)
+}
+
+func (r *TraceProtocolRepository) LookupName(ctx context.Context, addr net.IP) string {
+	if r.resolved == nil {
+		return ""
+	}
+	return r.resolved.LookupName(ctx, addr)
+}
+
+func (r *TraceProtocolRepository) repo(config *model.TraceConfig) (repository.TraceRepository, error) {
+	repo, ok := r.repos[config.Protocol()]
+	if !ok {
+		return nil, fmt.Errorf("trace は %s に対応していません", config.Protocol())
+	}
+	return repo, nil
+}
@@ -0,0 +1,120 @@
+package ping
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"nyagoPing/internal/domain/model"
+	"nyagoPing/internal/domain/repository"
+)
+
+func newSimulatedTraceConfig(t *testing.T, scenario *model.SimulationScenario) *model.TraceConfig {
+	t.Helper()
+	config, err := model.NewTraceConfig(model.DefaultMaxHops, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewTraceConfig() error = %v", err)
+	}
+	if err := config.SetSimulation(scenario); err != nil {
+		t.Fatalf("SetSimulation() error = %v", err)
+	}
+	return config
+}
+
+func TestSimulatedTraceRepository(t *testing.T) {
+	scenario, err := model.NewSimulationScenario(1, model.RttDistributionUniform, 2*time.Millisecond, 0, 0, nil, 0)
+	if err != nil {
+		t.Fatalf("NewSimulationScenario() error = %v", err)
+	}
+	config := newSimulatedTraceConfig(t, scenario)
+	repo := NewTraceProtocolRepository(map[model.Protocol]repository.TraceRepository{
+		model.ProtocolSimulated: NewSimulatedTraceRepository(),
+	})
+	ctx := context.Background()
+
+	target, _ := model.NewPingTarget("a.tld")
+	if err := repo.Resolve(ctx, target, config); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	var reached bool
+	for ttl := 1; ttl <= model.DefaultMaxHops; ttl++ {
+		reply, err := repo.ProbeHop(ctx, target, config, ttl)
+		if err != nil {
+			t.Fatalf("ProbeHop(%d) error = %v", ttl, err)
+		}
+		if reply.Addr == nil {
+			t.Fatalf("ProbeHop(%d) ロスのない筋書きで応答がありません", ttl)
+		}
+		if reply.Reached {
+			if !reply.Addr.Equal(target.IP()) {
+				t.Errorf("宛先の応答元 = %v, want %v", reply.Addr, target.IP())
+			}
+			if name := repo.LookupName(ctx, reply.Addr); name != "a.tld" {
+				t.Errorf("LookupName(宛先) = %q", name)
+			}
+			if ttl < simulatedMinHops || ttl > simulatedMaxHops {
+				t.Errorf("到達したホップ = %d", ttl)
+			}
+			reached = true
+			break
+		}
+		if want := simulatedRouterIP(ttl, model.IPFamilyV4); !reply.Addr.Equal(want) {
+			t.Errorf("ProbeHop(%d).Addr = %v, want %v", ttl, reply.Addr, want)
+		}
+		if name := repo.LookupName(ctx, reply.Addr); name == "" {
+			t.Errorf("LookupName(%v) が空です", reply.Addr)
+		}
+	}
+	if !reached {
+		t.Error("宛先に到達しませんでした")
+	}
+}
+
+func TestSimulatedTraceRepository_Loss(t *testing.T) {
+	scenario, err := model.NewSimulationScenario(1, model.RttDistributionUniform, time.Millisecond, 0, 1, nil, 0)
+	if err != nil {
+		t.Fatalf("NewSimulationScenario() error = %v", err)
+	}
+	config := newSimulatedTraceConfig(t, scenario)
+	repo := NewSimulatedTraceRepository()
+	target, _ := model.NewPingTarget("a.tld")
+	if err := repo.Resolve(context.Background(), target, config); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	reply, err := repo.ProbeHop(context.Background(), target, config, 1)
+	if err != nil {
+		t.Fatalf("ProbeHop() error = %v", err)
+	}
+	if reply.Addr != nil {
+		t.Errorf("ロス率1で応答がありました: %v", reply.Addr)
+	}
+}
+
+func TestTraceProtocolRepository_Unsupported(t *testing.T) {
+	config, _ := model.NewTraceConfig(model.DefaultMaxHops, time.Second)
+	repo := NewTraceProtocolRepository(map[model.Protocol]repository.TraceRepository{})
+	target, _ := model.NewPingTarget("a.tld")
+	if err := repo.Resolve(context.Background(), target, config); err == nil {
+		t.Error("Resolve() 未対応のプロトコルでエラーが発生しませんでした")
+	}
+}
+
+func TestICMPTraceRepository_Loopback(t *testing.T) {
+	config, _ := model.NewTraceConfig(model.DefaultMaxHops, time.Second)
+	repo := NewICMPTraceRepository()
+	target, _ := model.NewPingTarget("127.0.0.1")
+	if err := repo.Resolve(context.Background(), target, config); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	reply, err := repo.ProbeHop(context.Background(), target, config, 1)
+	if err != nil {
+		t.Skipf("raw ソケットを使えない環境です: %v", err)
+	}
+	if !reply.Reached || !reply.Addr.Equal(net.IPv4(127, 0, 0, 1)) {
+		t.Errorf("ProbeHop() = %+v, want 127.0.0.1 に到達", reply)
+	}
+}
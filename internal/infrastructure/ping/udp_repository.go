@@ -0,0 +1,164 @@
+package ping
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"nyagoPing/internal/domain/model"
+	"nyagoPing/internal/domain/repository"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultEchoSize    = 56
+	maxUDPResponseSize = 65535
+
+	dnsHeaderSize    = 12
+	dnsTypeA         = 1
+	dnsClassIN       = 1
+	dnsFlagRD        = 0x0100
+	dnsFlagQR        = 0x8000
+	dnsRcodeMask     = 0x000f
+	dnsRcodeNXDomain = 3
+)
+
+// UDPRepository はUDPの往復時間を計測します。
+// ProtocolUDP ではエコーサービスへペイロードを送り、同じ内容が返ってくるまでの時間を、
+// ProtocolDNS ではリゾルバへAレコードの問い合わせを送り、応答が返ってくるまでの時間を計測します。
+type UDPRepository struct{}
+
+func NewUDPRepository() repository.PingRepository {
+	return &UDPRepository{}
+}
+
+func (r *UDPRepository) Ping(
+	ctx context.Context,
+	target *model.PingTarget,
+	config *model.PingConfig,
+	art *model.ASCIIArt,
+	onRecv func(*model.PingPacket),
+	onEvent func(*model.PingEvent),
+	onFinish func(*model.PingStatistics),
+) error {
+	var buildRequest func(seq int) []byte
+	var checkResponse func(request, response []byte) error
+
+	switch config.Protocol() {
+	case model.ProtocolUDP:
+		payload := echoPayload(config.Size(), config.Pattern())
+		buildRequest = func(int) []byte {
+			return payload
+		}
+		checkResponse = func(request, response []byte) error {
+			if !bytes.Equal(request, response) {
+				return errors.New("エコーの内容が一致しません")
+			}
+			return nil
+		}
+	case model.ProtocolDNS:
+		if len(config.Pattern()) > 0 {
+			return fmt.Errorf("DNSモードではペイロードパターンを指定できません")
+		}
+		buildRequest = func(seq int) []byte {
+			return dnsQuery(uint16(seq), config.Query())
+		}
+		checkResponse = checkDNSResponse
+	default:
+		return fmt.Errorf("UDPリポジトリは %s に対応していません", config.Protocol())
+	}
+
+	if err := resolveTarget(target, config.Family()); err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(target.IP().String(), strconv.Itoa(config.Port()))
+	var dialer net.Dialer
+
+	runProbes(ctx, target, config, art, func(ctx context.Context, seq int) (*probeResult, error) {
+		// シーケンスごとにソケットを作り直し、遅れて届いた前の応答を受け取らないようにする
+		conn, err := dialer.DialContext(ctx, dialNetwork("udp", config.Family()), addr)
+		if err != nil {
+			return nil, err
+		}
+		defer conn.Close()
+		stop := context.AfterFunc(ctx, func() {
+			conn.SetDeadline(time.Now())
+		})
+		defer stop()
+
+		request := buildRequest(seq)
+		response := make([]byte, maxUDPResponseSize)
+
+		start := time.Now()
+		if _, err := conn.Write(request); err != nil {
+			return nil, err
+		}
+		n, err := conn.Read(response)
+		if err != nil {
+			return nil, err
+		}
+		rtt := time.Since(start)
+
+		if err := checkResponse(request, response[:n]); err != nil {
+			return nil, err
+		}
+		return &probeResult{nbytes: n, rtt: rtt}, nil
+	}, onRecv, onEvent, onFinish)
+
+	return nil
+}
+
+// echoPayload は size バイトのペイロードを pattern の繰り返しで埋めます。UDPエコーとパターン指定の ICMP Echo で使います。
+func echoPayload(size int, pattern []byte) []byte {
+	if size <= 0 {
+		size = defaultEchoSize
+	}
+	payload := make([]byte, size)
+	if len(pattern) > 0 {
+		for i := range payload {
+			payload[i] = pattern[i%len(pattern)]
+		}
+	}
+	return payload
+}
+
+// dnsQuery はRDフラグを立てたAレコードの問い合わせメッセージを組み立てます。
+func dnsQuery(id uint16, name string) []byte {
+	msg := make([]byte, dnsHeaderSize, dnsHeaderSize+len(name)+6)
+	binary.BigEndian.PutUint16(msg[0:], id)
+	binary.BigEndian.PutUint16(msg[2:], dnsFlagRD)
+	binary.BigEndian.PutUint16(msg[4:], 1)
+
+	for _, label := range strings.Split(name, ".") {
+		msg = append(msg, byte(len(label)))
+		msg = append(msg, label...)
+	}
+	msg = append(msg, 0)
+	msg = binary.BigEndian.AppendUint16(msg, dnsTypeA)
+	msg = binary.BigEndian.AppendUint16(msg, dnsClassIN)
+	return msg
+}
+
+// checkDNSResponse は問い合わせに対する応答かを確かめます。
+// NXDOMAIN はリゾルバが正常に答えたものとして受信扱いにし、SERVFAIL などはロスとします。
+func checkDNSResponse(request, response []byte) error {
+	if len(response) < dnsHeaderSize {
+		return fmt.Errorf("DNS応答が短すぎます: %dバイト", len(response))
+	}
+	if !bytes.Equal(request[0:2], response[0:2]) {
+		return errors.New("DNS応答のIDが一致しません")
+	}
+	flags := binary.BigEndian.Uint16(response[2:])
+	if flags&dnsFlagQR == 0 {
+		return errors.New("DNS応答ではありません")
+	}
+	if rcode := flags & dnsRcodeMask; rcode != 0 && rcode != dnsRcodeNXDomain {
+		return fmt.Errorf("DNS応答のRCODE %d", rcode)
+	}
+	return nil
+}
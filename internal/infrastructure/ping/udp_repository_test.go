@@ -0,0 +1,152 @@
+package ping
+
+import (
+	"encoding/binary"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"nyagoPing/internal/domain/model"
+)
+
+// startUDPServer は受け取ったメッセージを handler の戻り値で応答するUDPサーバーを起動します。
+func startUDPServer(t *testing.T, handler func([]byte) []byte) int {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 65535)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if reply := handler(buf[:n]); reply != nil {
+				conn.WriteTo(reply, addr)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+func TestUDPRepository_Ping_Echo(t *testing.T) {
+	port := startUDPServer(t, func(msg []byte) []byte {
+		return msg
+	})
+
+	config := newProtocolTestConfig(t, model.ProtocolUDP, port, 3, 200*time.Millisecond)
+	if err := config.SetSize(100); err != nil {
+		t.Fatalf("SetSize() error = %v", err)
+	}
+	if err := config.SetPattern([]byte{0xab}); err != nil {
+		t.Fatalf("SetPattern() error = %v", err)
+	}
+
+	packets, _, stats := collectPing(t, NewUDPRepository(), "127.0.0.1", config)
+	if len(packets) != 3 {
+		t.Fatalf("受信数 = %d, want 3", len(packets))
+	}
+	for i, packet := range packets {
+		if packet.Nbytes != 100 {
+			t.Errorf("packet[%d].Nbytes = %d, want 100", i, packet.Nbytes)
+		}
+	}
+	if stats == nil || stats.PacketsRecv != 3 {
+		t.Errorf("stats = %+v, want 3受信", stats)
+	}
+}
+
+func TestUDPRepository_Ping_EchoMismatchIsLoss(t *testing.T) {
+	port := startUDPServer(t, func(msg []byte) []byte {
+		return []byte("nyago")
+	})
+
+	_, events, stats := collectPing(t, NewUDPRepository(), "127.0.0.1", newProtocolTestConfig(t, model.ProtocolUDP, port, 2, 200*time.Millisecond))
+	if stats == nil || stats.PacketsSent != 2 || stats.PacketLoss != 100 {
+		t.Errorf("stats = %+v, want 2送信 100%%ロス", stats)
+	}
+	for i, event := range events {
+		if event.Type != model.PingEventLost || event.Err == nil {
+			t.Errorf("events[%d] = %+v, want 理由付きのロス", i, event)
+		}
+	}
+}
+
+func TestUDPRepository_Ping_DNS(t *testing.T) {
+	tests := []struct {
+		name     string
+		rcode    uint16
+		wantRecv int
+	}{
+		{name: "NOERROR", rcode: 0, wantRecv: 3},
+		{name: "NXDOMAIN", rcode: dnsRcodeNXDomain, wantRecv: 3},
+		{name: "SERVFAIL", rcode: 2, wantRecv: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var queries [][]byte
+			port := startUDPServer(t, func(msg []byte) []byte {
+				mu.Lock()
+				queries = append(queries, append([]byte(nil), msg...))
+				mu.Unlock()
+				reply := append([]byte(nil), msg...)
+				flags := binary.BigEndian.Uint16(reply[2:])
+				binary.BigEndian.PutUint16(reply[2:], flags|dnsFlagQR|tt.rcode)
+				return reply
+			})
+
+			config := newProtocolTestConfig(t, model.ProtocolDNS, port, 3, 200*time.Millisecond)
+			if err := config.SetQuery("example.tld"); err != nil {
+				t.Fatalf("SetQuery() error = %v", err)
+			}
+
+			packets, _, stats := collectPing(t, NewUDPRepository(), "127.0.0.1", config)
+			if len(packets) != tt.wantRecv {
+				t.Errorf("受信数 = %d, want %d", len(packets), tt.wantRecv)
+			}
+			if stats == nil || stats.PacketsSent != 3 {
+				t.Errorf("stats = %+v, want 3送信", stats)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if len(queries) == 0 {
+				t.Fatal("問い合わせが届いていません")
+			}
+			want := dnsQuery(0, "example.tld")
+			if string(queries[0]) != string(want) {
+				t.Errorf("query = %x, want %x", queries[0], want)
+			}
+		})
+	}
+}
+
+func TestUDPRepository_Ping_NoReplyTimesOut(t *testing.T) {
+	port := startUDPServer(t, func(msg []byte) []byte {
+		return nil
+	})
+
+	start := time.Now()
+	_, events, stats := collectPing(t, NewUDPRepository(), "127.0.0.1", newProtocolTestConfig(t, model.ProtocolUDP, port, 2, 200*time.Millisecond))
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("応答のないサーバーで %v かかりました", elapsed)
+	}
+	if stats == nil || stats.PacketLoss != 100 {
+		t.Errorf("stats = %+v, want 100%%ロス", stats)
+	}
+	if len(events) != 2 {
+		t.Fatalf("イベント数 = %d, want 2", len(events))
+	}
+	for i, event := range events {
+		if event.Type != model.PingEventTimeout || event.Seq != i {
+			t.Errorf("events[%d] = (%v, %d), want (timeout, %d)", i, event.Type, event.Seq, i)
+		}
+	}
+}
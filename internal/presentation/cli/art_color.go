@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"fmt"
+	"nyagoPing/internal/domain/model"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// colorProfile は端末で使える色の種類です。
+type colorProfile int
+
+const (
+	colorProfileNone colorProfile = iota
+	colorProfile256
+	colorProfileTrueColor
+)
+
+// detectColorProfile は COLORTERM から truecolor に対応しているかを判定します。対応していない端末では256色に落とします。
+func detectColorProfile() colorProfile {
+	if color.NoColor {
+		return colorProfileNone
+	}
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return colorProfileTrueColor
+	default:
+		return colorProfile256
+	}
+}
+
+// paintCells は文字ごとの色でアート行を描きます。色が変わる位置にだけ色の指定を挟み、行末で色を戻します。
+// backgrounds が nil なら背景色は端末の既定のままにします。
+func paintCells(line string, colors, backgrounds []model.RGB, profile colorProfile) string {
+	if profile == colorProfileNone || len(colors) == 0 {
+		return line
+	}
+
+	var b strings.Builder
+	i := 0
+	var current string
+	for _, ch := range line {
+		if i < len(colors) {
+			seq := colorSequence(38, colors[i], profile)
+			if i < len(backgrounds) {
+				seq += colorSequence(48, backgrounds[i], profile)
+			}
+			if seq != current {
+				b.WriteString(seq)
+				current = seq
+			}
+		}
+		b.WriteRune(ch)
+		i++
+	}
+	b.WriteString("\x1b[0m")
+	return b.String()
+}
+
+// colorSequence は SGR の文字色(38)または背景色(48)の指定を返します。
+func colorSequence(sgr int, c model.RGB, profile colorProfile) string {
+	if profile == colorProfileTrueColor {
+		return fmt.Sprintf("\x1b[%d;2;%d;%d;%dm", sgr, c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("\x1b[%d;5;%dm", sgr, ansi256(c))
+}
+
+// ansi256 は色を xterm の256色パレットのうち最も近い色に変換します。6×6×6 の色立方体と24段階の灰色のうち近い方を選びます。
+func ansi256(c model.RGB) int {
+	cube := func(v uint8) int {
+		if v < 48 {
+			return 0
+		}
+		if v < 115 {
+			return 1
+		}
+		return (int(v) - 35) / 40
+	}
+	levels := [6]int{0, 95, 135, 175, 215, 255}
+	r, g, b := cube(c.R), cube(c.G), cube(c.B)
+	cubeIndex := 16 + 36*r + 6*g + b
+	cubeDist := colorDistance(c, levels[r], levels[g], levels[b])
+
+	avg := (int(c.R) + int(c.G) + int(c.B)) / 3
+	gray := 23
+	if avg < 238 {
+		gray = max(0, (avg-3)/10)
+	}
+	grayLevel := 8 + 10*gray
+	if colorDistance(c, grayLevel, grayLevel, grayLevel) < cubeDist {
+		return 232 + gray
+	}
+	return cubeIndex
+}
+
+func colorDistance(c model.RGB, r, g, b int) int {
+	dr, dg, db := int(c.R)-r, int(c.G)-g, int(c.B)-b
+	return dr*dr + dg*dg + db*db
+}
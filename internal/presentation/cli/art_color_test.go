@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"testing"
+
+	"nyagoPing/internal/domain/model"
+)
+
+func TestPaintCells(t *testing.T) {
+	red, blue := model.RGB{R: 255}, model.RGB{B: 255}
+	colors := []model.RGB{red, red, blue}
+	tests := []struct {
+		name    string
+		profile colorProfile
+		want    string
+	}{
+		{"色なし", colorProfileNone, "ねこ$"},
+		{"truecolor", colorProfileTrueColor, "\x1b[38;2;255;0;0mねこ\x1b[38;2;0;0;255m$\x1b[0m"},
+		{"256色", colorProfile256, "\x1b[38;5;196mねこ\x1b[38;5;21m$\x1b[0m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := paintCells("ねこ$", colors, nil, tt.profile); got != tt.want {
+				t.Errorf("paintCells() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaintCells_Background(t *testing.T) {
+	red, blue := model.RGB{R: 255}, model.RGB{B: 255}
+	got := paintCells("▀▀", []model.RGB{red, red}, []model.RGB{blue, blue}, colorProfileTrueColor)
+	if want := "\x1b[38;2;255;0;0m\x1b[48;2;0;0;255m▀▀\x1b[0m"; got != want {
+		t.Errorf("paintCells() = %q, want %q", got, want)
+	}
+}
+
+func TestANSI256(t *testing.T) {
+	tests := []struct {
+		c    model.RGB
+		want int
+	}{
+		{model.RGB{}, 16},
+		{model.RGB{R: 255, G: 255, B: 255}, 231},
+		{model.RGB{R: 255}, 196},
+		{model.RGB{R: 128, G: 128, B: 128}, 244},
+		{model.RGB{R: 95, G: 135, B: 175}, 67},
+	}
+	for _, tt := range tests {
+		if got := ansi256(tt.c); got != tt.want {
+			t.Errorf("ansi256(%v) = %d, want %d", tt.c, got, tt.want)
+		}
+	}
+}
@@ -1,13 +1,21 @@
 package cli
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"nyagoPing/internal/application/usecase"
 	"nyagoPing/internal/domain/model"
+	"nyagoPing/internal/domain/service"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"time"
 
+	"github.com/fatih/color"
 	"github.com/jessevdk/go-flags"
 )
 
@@ -19,54 +27,154 @@ const (
 
 type exitCode int
 
+// serveShutdownTimeout は serve と web の終了時に処理中のリクエストを待つ上限です。
+const serveShutdownTimeout = 5 * time.Second
+
+// --listen を省略した場合のアドレスです。/metrics は他のホストから集めるため全インターフェースで公開し、
+// web のダッシュボードは手元のブラウザで見るため自分のホストからだけ開けるようにします。
+const (
+	defaultServeListen = ":9101"
+	defaultWebListen   = "127.0.0.1:9101"
+)
+
+const (
+	formatText   = "text"
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
+)
+
 type Options struct {
-	Count          int    `short:"c" long:"count" description:"Pingの送信回数を指定します。"`
-	Privilege      bool   `short:"p" long:"privileged" description:"特権モードで実行します。"`
-	Version        bool   `short:"v" long:"version" description:"バージョンを表示します。"`
-	ASCIIArtPath   string `short:"a" long:"ascii-art" description:"アスキーアートファイルのパスを指定します。" default:".env"`
-	Generate       string `short:"g" long:"generate" description:"画像ファイルまたはディレクトリからアスキーアートを生成します。"`
-	GenerateOutput string `short:"o" long:"output" description:"生成したアスキーアートの出力先を指定します。" default:".env"`
-	GenerateWidth  int    `short:"w" long:"width" description:"生成するアスキーアートの幅を指定します。" default:"80"`
+	Count          int           `short:"c" long:"count" description:"Pingの送信回数を指定します。"`
+	Privilege      bool          `short:"p" long:"privileged" description:"特権モードで実行します。"`
+	Interval       time.Duration `short:"i" long:"interval" description:"Pingの送信間隔を指定します。" default:"1s"`
+	Timeout        time.Duration `short:"W" long:"timeout" description:"1パケットあたりの応答待ち時間を指定します。0で無制限です。" default:"2s"`
+	Deadline       time.Duration `long:"deadline" description:"実行全体の制限時間を指定します。0で無制限です。" default:"0"`
+	Size           int           `short:"s" long:"size" description:"ペイロードのバイト数を指定します。0で既定値です。"`
+	TTL            int           `short:"t" long:"ttl" description:"送信パケットのTTL(ホップリミット)を指定します。0で既定値です。"`
+	Pattern        string        `long:"pattern" description:"ペイロードを埋めるパターンを16進数で指定します。(例: ff00)"`
+	IPv4           bool          `short:"4" description:"IPv4のみを使用します。"`
+	IPv6           bool          `short:"6" description:"IPv6のみを使用します。"`
+	TCPPort        int           `long:"tcp" value-name:"PORT" description:"ICMPの代わりに指定ポートへのTCP接続時間を計測します。"`
+	HTTPMethod     string        `long:"method" description:"URLを指定した場合に送るHTTPメソッドを指定します。" choice:"HEAD" choice:"GET" default:"HEAD"`
+	UDPPort        int           `long:"udp" value-name:"PORT" description:"指定ポートのUDPエコーサービスとの往復時間を計測します。"`
+	DNSQuery       string        `long:"dns" value-name:"NAME" description:"ホストをDNSリゾルバとしてNAMEのAレコードを問い合わせ、応答時間を計測します。"`
+	DNSPort        int           `long:"dns-port" description:"DNSモードで問い合わせるポートを指定します。" default:"53"`
+	Simulate       bool          `long:"simulate" description:"ネットワークを使わずに応答を再現します。"`
+	Scenario       string        `long:"scenario" value-name:"FILE" description:"シミュレーションの筋書き(JSON)を指定します。--simulate を含みます。"`
+	Seed           int64         `long:"seed" description:"シミュレーションの乱数シードを指定します。0で毎回変わります。複数ホストでは2つ目以降のホストにシードとホストの順番を混ぜたシードを使います。"`
+	Record         string        `long:"record" value-name:"FILE" description:"受信したパケット、タイムアウトなどのイベント、統計をセッションファイル(JSON Lines)に記録します。"`
+	CSV            string        `long:"csv" value-name:"FILE" description:"パケットごとの結果をCSVに書き出します。統計は FILE と同じ場所の .summary.csv に書きます。"`
+	Speed          float64       `long:"speed" description:"replay の再生速度の倍率を指定します。0で待たずに再生します。" default:"1"`
+	WarnRtt        time.Duration `long:"warn-rtt" description:"アート行を黄色で描くRTTを指定します。(既定: 30ms)"`
+	BadRtt         time.Duration `long:"bad-rtt" description:"アート行を赤色で描くRTTを指定します。(既定: 100ms)"`
+	Thresholds     string        `long:"thresholds" value-name:"FILE" description:"RTTの閾値ファイル(JSON)を指定します。--warn-rtt と --bad-rtt が優先されます。"`
+	Format         string        `long:"format" description:"出力形式を指定します。json は最後に1つの文書を、ndjson はパケットごとに1行を出力します。" choice:"text" choice:"json" choice:"ndjson" default:"text"`
+	TUI            bool          `long:"tui" description:"全画面でアートを描き、統計とRTTの推移をその場で更新します。q で停止します。"`
+	MaxHops        int           `long:"max-hops" description:"trace と mtr で宛先に届かない場合に打ち切るホップ数を指定します。" default:"30"`
+	Listen         string        `long:"listen" description:"serve の /metrics や web のダッシュボードを公開するアドレスを指定します。省略すると serve は :9101、web は 127.0.0.1:9101 です。"`
+	Version        bool          `short:"v" long:"version" description:"バージョンを表示します。"`
+	ASCIIArtPath   string        `short:"a" long:"ascii-art" description:"アスキーアートファイルのパスを指定します。" default:".env"`
+	Generate       string        `short:"g" long:"generate" description:"画像ファイルまたはディレクトリからアスキーアートを生成します。"`
+	GenerateOutput string        `short:"o" long:"output" description:"生成したアスキーアートの出力先を指定します。" default:".env"`
+	GenerateWidth  int           `short:"w" long:"width" description:"生成するアスキーアートの幅を指定します。" default:"80"`
+	GenerateColor  bool          `long:"color" description:"画像の色を残したアスキーアートを生成します。truecolor に対応していない端末では256色で表示します。"`
+	GenerateMode   string        `long:"mode" description:"画像を文字に変換する方式を指定します。blocks は半ブロックで縦2倍、braille は点字で縦4倍・横2倍の解像度になります。" choice:"ascii" choice:"blocks" choice:"braille" default:"ascii"`
+	GenerateDither string        `long:"dither" description:"生成時のディザリング方式を指定します。グラデーションの縞を目立たなくします。" choice:"none" choice:"floyd-steinberg" choice:"atkinson" choice:"ordered" default:"none"`
+	Invert         bool          `long:"invert" description:"明るい部分ほど薄い文字で生成します。明るい背景の端末向けです。--mode blocks と --color を併せた場合は色を反転します。"`
+	Contrast       float64       `long:"contrast" description:"生成時のコントラストの倍率を指定します。" default:"1"`
+	Brightness     float64       `long:"brightness" description:"生成時に明るさ(0〜1)に足す量を指定します。負の値で暗くなります。" default:"0"`
+	Gamma          float64       `long:"gamma" description:"生成時のガンマ補正の値を指定します。1より大きいと暗部が明るくなります。" default:"1"`
+	AutoLevels     bool          `long:"auto-levels" description:"画像の明暗の幅を目いっぱいに引き伸ばしてから生成します。"`
 }
 
 type CLI struct {
-	pingUseCase     *usecase.PingUseCase
-	generateUseCase *usecase.GenerateASCIIArtUseCase
-	presenter       *Presenter
-	appName         string
-	appVersion      string
-	appDescription  string
+	pingUseCase        *usecase.PingUseCase
+	multiPingUseCase   *usecase.MultiPingUseCase
+	monitorUseCase     *usecase.MonitorUseCase
+	replayUseCase      *usecase.ReplayUseCase
+	traceUseCase       *usecase.TraceUseCase
+	mtrUseCase         *usecase.MTRUseCase
+	generateUseCase    *usecase.GenerateASCIIArtUseCase
+	textPresenter      PingPresenter
+	textMultiPresenter MultiPingPresenter
+	tracePresenter     TracePresenter
+	// presenter と multiPresenter は --format に応じて run で切り替わります
+	presenter      PingPresenter
+	multiPresenter MultiPingPresenter
+	appName        string
+	appVersion     string
+	appDescription string
 }
 
 func NewCLI(
 	pingUseCase *usecase.PingUseCase,
+	multiPingUseCase *usecase.MultiPingUseCase,
+	monitorUseCase *usecase.MonitorUseCase,
+	replayUseCase *usecase.ReplayUseCase,
+	traceUseCase *usecase.TraceUseCase,
+	mtrUseCase *usecase.MTRUseCase,
 	generateUseCase *usecase.GenerateASCIIArtUseCase,
-	presenter *Presenter,
+	presenter PingPresenter,
+	multiPresenter MultiPingPresenter,
+	tracePresenter TracePresenter,
 	appName, appVersion, appDescription string,
 ) *CLI {
 	return &CLI{
-		pingUseCase:     pingUseCase,
-		generateUseCase: generateUseCase,
-		presenter:       presenter,
-		appName:         appName,
-		appVersion:      appVersion,
-		appDescription:  appDescription,
+		pingUseCase:        pingUseCase,
+		multiPingUseCase:   multiPingUseCase,
+		monitorUseCase:     monitorUseCase,
+		replayUseCase:      replayUseCase,
+		traceUseCase:       traceUseCase,
+		mtrUseCase:         mtrUseCase,
+		generateUseCase:    generateUseCase,
+		textPresenter:      presenter,
+		textMultiPresenter: multiPresenter,
+		presenter:          presenter,
+		multiPresenter:     multiPresenter,
+		tracePresenter:     tracePresenter,
+		appName:            appName,
+		appVersion:         appVersion,
+		appDescription:     appDescription,
 	}
 }
 
-func (c *CLI) Run(args []string) exitCode {
-	code, err := c.run(args)
+func (c *CLI) Run(ctx context.Context, args []string) exitCode {
+	code, err := c.run(ctx, args)
+	if err == nil {
+		if err = c.outputErr(); err != nil {
+			code = ExitCodeErrorExecution
+		}
+	}
 	if err != nil {
 		c.presenter.ShowError(err)
 	}
 	return code
 }
 
-func (c *CLI) run(cliArgs []string) (exitCode, error) {
+// outputErrorer は出力先への書き込みの失敗を後から返せる presenter です。
+type outputErrorer interface {
+	OutputErr() error
+}
+
+// outputErr は presenter の出力に失敗していれば最初のエラーを返します。パイプの先が閉じた場合などに出力の欠けを知らせます。
+func (c *CLI) outputErr() error {
+	for _, p := range []any{c.presenter, c.multiPresenter} {
+		if o, ok := p.(outputErrorer); ok {
+			if err := o.OutputErr(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *CLI) run(ctx context.Context, cliArgs []string) (exitCode, error) {
+	c.presenter, c.multiPresenter = c.textPresenter, c.textMultiPresenter
+
 	var opts Options
 	parser := flags.NewParser(&opts, flags.Default)
 	parser.Name = c.appName
-	parser.Usage = fmt.Sprintf("[オプション...] <ホスト>\n\n%s", c.appDescription)
+	parser.Usage = fmt.Sprintf("[オプション...] <ホスト>...\n  %s [オプション...] replay <セッションファイル>\n  %s [オプション...] serve <ホスト>...\n  %s [オプション...] web <ホスト>...\n  %s [オプション...] trace <ホスト>\n  %s [オプション...] mtr <ホスト>\n\n%s", c.appName, c.appName, c.appName, c.appName, c.appName, c.appDescription)
 
 	args, err := parser.ParseArgs(cliArgs)
 	if err != nil {
@@ -76,6 +184,12 @@ func (c *CLI) run(cliArgs []string) (exitCode, error) {
 		return ExitCodeErrorArgs, fmt.Errorf("引数解析エラー: %w", err)
 	}
 
+	if opts.Format != formatText {
+		stream := opts.Format == formatNDJSON
+		c.presenter = NewJSONPresenter(os.Stdout, os.Stderr, stream)
+		c.multiPresenter = NewJSONMultiPresenter(os.Stdout, os.Stderr, stream)
+	}
+
 	if opts.Version {
 		c.presenter.ShowVersion(c.appName, c.appVersion)
 		return ExitCodeOK, nil
@@ -85,14 +199,57 @@ func (c *CLI) run(cliArgs []string) (exitCode, error) {
 		return c.handleGenerate(&opts)
 	}
 
+	if len(args) > 0 && args[0] == "replay" {
+		if len(args) != 2 {
+			return ExitCodeErrorArgs, errors.New("セッションファイルを1つ指定してください")
+		}
+		return c.handleReplay(ctx, &opts, args[1])
+	}
+
+	if len(args) > 0 && (args[0] == "trace" || args[0] == "mtr") {
+		if len(args) != 2 {
+			return ExitCodeErrorArgs, errors.New("ホスト名を1つ指定してください")
+		}
+		if opts.Format != formatText {
+			return ExitCodeErrorArgs, fmt.Errorf("%s は --format に対応していません", args[0])
+		}
+		if args[0] == "mtr" {
+			return c.handleMTR(ctx, &opts, args[1])
+		}
+		return c.handleTrace(ctx, &opts, args[1])
+	}
+
+	if len(args) > 0 && args[0] == "web" {
+		if len(args) < 2 {
+			return ExitCodeErrorArgs, errors.New("ホスト名を指定してください")
+		}
+		return c.handleWeb(ctx, &opts, args[1:])
+	}
+
+	if len(args) > 0 && args[0] == "serve" {
+		if len(args) < 2 {
+			return ExitCodeErrorArgs, errors.New("ホスト名を指定してください")
+		}
+		return c.handleServe(ctx, &opts, args[1:])
+	}
+
 	if len(args) == 0 {
 		return ExitCodeErrorArgs, errors.New("ホスト名を指定してください")
 	}
 	if len(args) > 1 {
-		return ExitCodeErrorArgs, errors.New("ホスト名は1つだけ指定してください")
+		if opts.TUI {
+			return ExitCodeErrorArgs, errors.New("--tui は1つのホストにのみ使えます")
+		}
+		return c.handleMultiPing(ctx, &opts, args)
+	}
+	if opts.TUI {
+		if opts.Format != formatText {
+			return ExitCodeErrorArgs, errors.New("--tui と --format は同時に指定できません")
+		}
+		return c.handleTUI(ctx, &opts, args[0])
 	}
 
-	return c.handlePing(&opts, args[0])
+	return c.handlePing(ctx, &opts, args[0])
 }
 
 func (c *CLI) handleGenerate(opts *Options) (exitCode, error) {
@@ -107,6 +264,14 @@ func (c *CLI) handleGenerate(opts *Options) (exitCode, error) {
 	input := &usecase.GenerateInput{
 		OutputPath: outputPath,
 		Width:      opts.GenerateWidth,
+		Color:      opts.GenerateColor,
+		Mode:       service.RenderMode(opts.GenerateMode),
+		Dither:     service.DitherMode(opts.GenerateDither),
+		Invert:     opts.Invert,
+		Contrast:   &opts.Contrast,
+		Brightness: opts.Brightness,
+		Gamma:      &opts.Gamma,
+		AutoLevels: opts.AutoLevels,
 	}
 
 	fileInfo, err := os.Stat(opts.Generate)
@@ -144,44 +309,354 @@ func (c *CLI) handleGenerate(opts *Options) (exitCode, error) {
 	return ExitCodeOK, nil
 }
 
-func (c *CLI) handlePing(opts *Options, host string) (exitCode, error) {
-	count := opts.Count
-	autoCount := count == 0
+func (c *CLI) handlePing(ctx context.Context, opts *Options, host string) (exitCode, error) {
+	input := c.pingInput(opts)
+	input.Host = host
 
-	asciiArtPath := opts.ASCIIArtPath
-	if asciiArtPath == ".env" {
-		execPath, err := os.Executable()
-		if err == nil {
-			asciiArtPath = filepath.Join(filepath.Dir(execPath), ".env")
+	err := c.pingUseCase.Execute(
+		ctx,
+		input,
+		func(packet *model.PingPacket) {
+			c.presenter.ShowPingPacket(packet)
+		},
+		func(event *model.PingEvent) {
+			c.presenter.ShowPingEvent(event)
+		},
+		func(stats *model.PingStatistics) {
+			c.presenter.ShowPingStatistics(stats)
+		},
+	)
+
+	if err != nil {
+		return ExitCodeErrorExecution, err
+	}
+
+	return ExitCodeOK, nil
+}
+
+// handleTUI は全画面の TUIPresenter で1ホストにPingします。q が押されたら Ping を止め、最終統計を元の画面に残します。
+func (c *CLI) handleTUI(ctx context.Context, opts *Options, host string) (exitCode, error) {
+	input := c.pingInput(opts)
+	input.Host = host
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stdin := int(os.Stdin.Fd())
+	if restore, err := enableKeyInput(stdin); err == nil {
+		defer restore()
+	}
+	go watchQuitKey(os.Stdin, cancel)
+
+	tui := NewTUIPresenter(color.Output, c.presenter, host, func() (int, int) {
+		if width, height, ok := terminalSize(int(os.Stdout.Fd())); ok {
+			return width, height
 		}
+		return tuiDefaultWidth, tuiDefaultHeight
+	})
+	tui.Start()
+	err := c.pingUseCase.Execute(ctx, input, tui.ShowPingPacket, tui.ShowPingEvent, tui.ShowPingStatistics)
+	tui.Stop()
+
+	if err != nil {
+		return ExitCodeErrorExecution, err
 	}
+	return ExitCodeOK, nil
+}
 
-	input := &usecase.PingInput{
-		Host:           host,
-		Count:          count,
-		Privileged:     opts.Privilege,
-		ASCIIArtPath:   asciiArtPath,
-		AutoCountByArt: autoCount,
+// watchQuitKey は r から q を読んだら stop を呼びます。
+func watchQuitKey(r io.Reader, stop func()) {
+	buf := make([]byte, 1)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			return
+		}
+		if n == 1 && (buf[0] == 'q' || buf[0] == 'Q') {
+			stop()
+			return
+		}
 	}
+}
 
-	err := c.pingUseCase.Execute(
+func (c *CLI) handleMultiPing(ctx context.Context, opts *Options, hosts []string) (exitCode, error) {
+	input := &usecase.MultiPingInput{
+		PingInput: *c.pingInput(opts),
+		Hosts:     hosts,
+	}
+
+	c.multiPresenter.ShowPingStart(hosts)
+	err := c.multiPingUseCase.Execute(
+		ctx,
 		input,
-		func(packet *model.PingPacket) {
+		func(lane int, packet *model.PingPacket) {
+			c.multiPresenter.ShowPingPacket(lane, packet)
+		},
+		func(lane int, event *model.PingEvent) {
+			c.multiPresenter.ShowPingEvent(lane, event)
+		},
+		func(stats []*model.PingStatistics) {
+			c.multiPresenter.ShowPingStatistics(stats)
+		},
+	)
+
+	if err != nil {
+		return ExitCodeErrorExecution, err
+	}
+
+	return ExitCodeOK, nil
+}
+
+func (c *CLI) handleReplay(ctx context.Context, opts *Options, sessionPath string) (exitCode, error) {
+	input := &usecase.ReplayInput{
+		SessionPath:    sessionPath,
+		Speed:          opts.Speed,
+		ThresholdInput: thresholdInput(opts),
+	}
+
+	var multi bool
+	var stats []*model.PingStatistics
+	err := c.replayUseCase.Execute(
+		ctx,
+		input,
+		func(hosts []string) {
+			multi = len(hosts) > 1
+			stats = make([]*model.PingStatistics, len(hosts))
+			if multi {
+				c.multiPresenter.ShowPingStart(hosts)
+			}
+		},
+		func(lane int, packet *model.PingPacket) {
+			if multi {
+				c.multiPresenter.ShowPingPacket(lane, packet)
+				return
+			}
 			c.presenter.ShowPingPacket(packet)
 		},
-		func(stats *model.PingStatistics) {
-			c.presenter.ShowPingStatistics(stats)
+		func(lane int, event *model.PingEvent) {
+			if multi {
+				c.multiPresenter.ShowPingEvent(lane, event)
+				return
+			}
+			c.presenter.ShowPingEvent(event)
+		},
+		func(lane int, s *model.PingStatistics) {
+			if multi {
+				stats[lane] = s
+				return
+			}
+			c.presenter.ShowPingStatistics(s)
 		},
 	)
+	if multi {
+		c.multiPresenter.ShowPingStatistics(stats)
+	}
+
+	if err != nil {
+		return ExitCodeErrorExecution, err
+	}
+
+	return ExitCodeOK, nil
+}
+
+// handleTrace は host までの経路をホップごとに1行ずつ表示します。
+func (c *CLI) handleTrace(ctx context.Context, opts *Options, host string) (exitCode, error) {
+	input := traceInput(opts, host)
+	err := c.traceUseCase.Execute(ctx, input, c.tracePresenter.ShowTraceStart, c.tracePresenter.ShowTraceHop)
+	if err != nil {
+		return ExitCodeErrorExecution, err
+	}
+	return ExitCodeOK, nil
+}
+
+// handleMTR は host までの各ホップへのプローブを -i の間隔で繰り返し、ホップごとの集計表を更新し続けます。
+// -c を指定するとその巡数で終わり、指定しなければ中断されるまで続けます。
+func (c *CLI) handleMTR(ctx context.Context, opts *Options, host string) (exitCode, error) {
+	input := &usecase.MTRInput{
+		TraceInput: *traceInput(opts, host),
+		Interval:   opts.Interval,
+		Count:      opts.Count,
+	}
+
+	_, _, inPlace := terminalSize(int(os.Stdout.Fd()))
+	presenter := NewMTRPresenter(color.Output, inPlace)
+	err := c.mtrUseCase.Execute(ctx, input, presenter.ShowMTRStart, presenter.ShowMTRUpdate)
+	presenter.Finish()
 
 	if err != nil {
 		return ExitCodeErrorExecution, err
 	}
+	return ExitCodeOK, nil
+}
+
+// handleServe は hosts へのPingを中断されるまで続け、結果を /metrics で Prometheus 形式で公開します。
+func (c *CLI) handleServe(ctx context.Context, opts *Options, hosts []string) (exitCode, error) {
+	registry := NewMetricsRegistry(hosts)
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", registry)
+
+	return c.serveMonitor(ctx, opts, defaultServeListen, hosts, mux,
+		func(addr net.Addr) {
+			fmt.Printf("http://%s/metrics で %d 件のホストのメトリクスを公開しています\n", addr, len(hosts))
+		},
+		registry.ObservePacket,
+		registry.ObserveEvent,
+		func(int, *model.PingStatistics) {},
+		func(lane int, err error) {
+			registry.ObserveFailure(lane)
+			c.presenter.ShowError(err)
+		},
+		func() {},
+	)
+}
+
+// handleWeb は hosts へのPingを中断されるまで続け、アートとRTTの推移をブラウザ向けのダッシュボードで配信します。
+func (c *CLI) handleWeb(ctx context.Context, opts *Options, hosts []string) (exitCode, error) {
+	dashboard := NewDashboard()
+	dashboard.ShowPingStart(hosts)
+
+	return c.serveMonitor(ctx, opts, defaultWebListen, hosts, dashboard,
+		func(addr net.Addr) {
+			fmt.Printf("http://%s/ で %d 件のホストのダッシュボードを公開しています\n", addr, len(hosts))
+		},
+		dashboard.ShowPingPacket,
+		dashboard.ShowPingEvent,
+		dashboard.ShowHostStatistics,
+		func(lane int, err error) {
+			dashboard.ShowPingFailure(lane, err)
+			c.presenter.ShowError(err)
+		},
+		// SSE の接続は終わらないため、サーバーを止める前に配信を閉じる
+		dashboard.Close,
+	)
+}
+
+// serveMonitor は --listen(省略時は defaultListen)で handler を公開しながら hosts を監視し、中断されたらサーバーを止めます。
+// beforeShutdown は監視が終わってからサーバーを止めるまでの間に呼ばれます。
+func (c *CLI) serveMonitor(
+	ctx context.Context,
+	opts *Options,
+	defaultListen string,
+	hosts []string,
+	handler http.Handler,
+	onListen func(net.Addr),
+	onRecv func(int, *model.PingPacket),
+	onEvent func(int, *model.PingEvent),
+	onFinish func(int, *model.PingStatistics),
+	onError func(int, error),
+	beforeShutdown func(),
+) (exitCode, error) {
+	address := opts.Listen
+	if address == "" {
+		address = defaultListen
+	}
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return ExitCodeErrorExecution, fmt.Errorf("待ち受けエラー: %w", err)
+	}
+	server := &http.Server{Handler: handler}
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	serveErr := make(chan error, 1)
+	go func() {
+		err := server.Serve(listener)
+		if !errors.Is(err, http.ErrServerClosed) {
+			cancel()
+		}
+		serveErr <- err
+	}()
+
+	onListen(listener.Addr())
+
+	input := &usecase.MultiPingInput{
+		PingInput: *c.pingInput(opts),
+		Hosts:     hosts,
+	}
+	err = c.monitorUseCase.Execute(ctx, input, onRecv, onEvent, onFinish, onError)
+
+	beforeShutdown()
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+	defer shutdownCancel()
+	server.Shutdown(shutdownCtx)
+	if sErr := <-serveErr; !errors.Is(sErr, http.ErrServerClosed) {
+		return ExitCodeErrorExecution, fmt.Errorf("HTTPサーバーエラー: %w", sErr)
+	}
+
+	if err != nil {
+		return ExitCodeErrorExecution, err
+	}
 	return ExitCodeOK, nil
 }
 
+func (c *CLI) pingInput(opts *Options) *usecase.PingInput {
+	count := opts.Count
+	autoCount := count == 0
+
+	return &usecase.PingInput{
+		Count:          count,
+		Privileged:     opts.Privilege,
+		Interval:       opts.Interval,
+		Timeout:        opts.Timeout,
+		Deadline:       opts.Deadline,
+		Size:           opts.Size,
+		TTL:            opts.TTL,
+		Pattern:        opts.Pattern,
+		ForceIPv4:      opts.IPv4,
+		ForceIPv6:      opts.IPv6,
+		TCPPort:        opts.TCPPort,
+		HTTPMethod:     opts.HTTPMethod,
+		UDPPort:        opts.UDPPort,
+		DNSQuery:       opts.DNSQuery,
+		DNSPort:        opts.DNSPort,
+		Simulate:       opts.Simulate,
+		ScenarioPath:   opts.Scenario,
+		Seed:           opts.Seed,
+		RecordPath:     opts.Record,
+		CSVPath:        opts.CSV,
+		ASCIIArtPath:   asciiArtPath(opts),
+		AutoCountByArt: autoCount,
+		ThresholdInput: thresholdInput(opts),
+	}
+}
+
+func traceInput(opts *Options, host string) *usecase.TraceInput {
+	return &usecase.TraceInput{
+		Host:         host,
+		MaxHops:      opts.MaxHops,
+		Timeout:      opts.Timeout,
+		ForceIPv4:    opts.IPv4,
+		ForceIPv6:    opts.IPv6,
+		Simulate:     opts.Simulate,
+		ScenarioPath: opts.Scenario,
+		Seed:         opts.Seed,
+		ASCIIArtPath: asciiArtPath(opts),
+	}
+}
+
+// asciiArtPath は既定の .env を実行ファイルと同じディレクトリから探すよう解決します。
+func asciiArtPath(opts *Options) string {
+	if opts.ASCIIArtPath != ".env" {
+		return opts.ASCIIArtPath
+	}
+	execPath, err := os.Executable()
+	if err != nil {
+		return opts.ASCIIArtPath
+	}
+	return filepath.Join(filepath.Dir(execPath), ".env")
+}
+
+func thresholdInput(opts *Options) usecase.ThresholdInput {
+	return usecase.ThresholdInput{
+		WarnRtt:        opts.WarnRtt,
+		BadRtt:         opts.BadRtt,
+		ThresholdsPath: opts.Thresholds,
+	}
+}
+
 func (c *CLI) Main() {
-	code := c.Run(os.Args[1:])
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := c.Run(ctx, os.Args[1:])
+	stop()
 	os.Exit(int(code))
 }
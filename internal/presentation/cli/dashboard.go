@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"nyagoPing/internal/domain/model"
+	"sync"
+)
+
+//go:embed dashboard
+var dashboardAssets embed.FS
+
+const (
+	// dashboardBacklog は途中から開いたページにも現在のアートを描けるよう保持する直近のメッセージ数です。
+	dashboardBacklog = 1000
+	// dashboardClientBuffer を超えて溜まったメッセージは、Pingを止めないよう遅いクライアントに送らず捨てます。
+	dashboardClientBuffer = 256
+)
+
+type dashboardMessage struct {
+	event string
+	data  []byte
+}
+
+type dashboardPacket struct {
+	Lane       int     `json:"lane"`
+	Seq        int     `json:"seq"`
+	Bytes      int     `json:"bytes,omitempty"`
+	IP         string  `json:"ip,omitempty"`
+	TTL        int     `json:"ttl,omitempty"`
+	RttMs      float64 `json:"rttMs"`
+	ArtLine    string  `json:"artLine"`
+	StatusCode int     `json:"statusCode,omitempty"`
+	Level      string  `json:"level,omitempty"`
+}
+
+type dashboardEvent struct {
+	Lane       int     `json:"lane"`
+	Type       string  `json:"type"`
+	Seq        int     `json:"seq"`
+	RttMs      float64 `json:"rttMs,omitempty"`
+	ArtLine    string  `json:"artLine"`
+	StatusCode int     `json:"statusCode,omitempty"`
+	Error      string  `json:"error,omitempty"`
+}
+
+type dashboardStatistics struct {
+	Lane   int     `json:"lane"`
+	Sent   int     `json:"sent"`
+	Recv   int     `json:"recv"`
+	Loss   float64 `json:"loss"`
+	AvgMs  float64 `json:"avgMs"`
+	P95Ms  float64 `json:"p95Ms"`
+	Jitter float64 `json:"jitterMs"`
+}
+
+// Dashboard は受信したパケットを Server-Sent Events で配信し、それを描く埋め込みのページを提供します。
+// MultiPingPresenter として複数のゴルーチンから同時に呼び出せます。
+type Dashboard struct {
+	mu      sync.Mutex
+	hosts   []string
+	backlog []dashboardMessage
+	clients map[chan dashboardMessage]struct{}
+	closed  bool
+	mux     *http.ServeMux
+}
+
+func NewDashboard() *Dashboard {
+	d := &Dashboard{
+		clients: make(map[chan dashboardMessage]struct{}),
+		mux:     http.NewServeMux(),
+	}
+	assets, _ := fs.Sub(dashboardAssets, "dashboard")
+	d.mux.Handle("/", http.FileServer(http.FS(assets)))
+	d.mux.HandleFunc("/events", d.serveEvents)
+	return d
+}
+
+func (d *Dashboard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mux.ServeHTTP(w, r)
+}
+
+func (d *Dashboard) ShowPingStart(hosts []string) {
+	d.mu.Lock()
+	d.hosts = hosts
+	d.backlog = nil
+	d.mu.Unlock()
+	d.publish("hosts", hosts)
+}
+
+func (d *Dashboard) ShowPingPacket(lane int, packet *model.PingPacket) {
+	data := &dashboardPacket{
+		Lane:       lane,
+		Seq:        packet.Seq,
+		Bytes:      packet.Nbytes,
+		TTL:        packet.TTL,
+		RttMs:      millis(packet.Rtt.Seconds()),
+		ArtLine:    packet.ArtLine,
+		StatusCode: packet.StatusCode,
+	}
+	if packet.IPAddr != nil {
+		data.IP = packet.IPAddr.String()
+	}
+	if packet.Level != model.LatencyUnknown {
+		data.Level = packet.Level.String()
+	}
+	d.publish("packet", data)
+}
+
+func (d *Dashboard) ShowPingEvent(lane int, event *model.PingEvent) {
+	data := &dashboardEvent{
+		Lane:       lane,
+		Type:       event.Type.String(),
+		Seq:        event.Seq,
+		RttMs:      millis(event.Rtt.Seconds()),
+		ArtLine:    event.ArtLine,
+		StatusCode: event.StatusCode,
+	}
+	if event.Err != nil {
+		data.Error = event.Err.Error()
+	}
+	d.publish("event", data)
+}
+
+func (d *Dashboard) ShowPingStatistics(stats []*model.PingStatistics) {
+	for lane, s := range stats {
+		if s != nil {
+			d.ShowHostStatistics(lane, s)
+		}
+	}
+}
+
+// ShowHostStatistics は1ホストの1巡分の統計をページに伝えます。
+func (d *Dashboard) ShowHostStatistics(lane int, stats *model.PingStatistics) {
+	d.publish("statistics", &dashboardStatistics{
+		Lane:   lane,
+		Sent:   stats.PacketsSent,
+		Recv:   stats.PacketsRecv,
+		Loss:   stats.PacketLoss,
+		AvgMs:  millis(stats.AvgRtt.Seconds()),
+		P95Ms:  millis(stats.P95Rtt.Seconds()),
+		Jitter: millis(stats.Jitter.Seconds()),
+	})
+}
+
+// ShowPingFailure はホストの1巡が失敗したことをページに伝えます。
+func (d *Dashboard) ShowPingFailure(lane int, err error) {
+	d.publish("failure", map[string]any{"lane": lane, "error": err.Error()})
+}
+
+// Close は接続中の全クライアントへの配信を終えます。HTTPサーバーを止める前に呼んでください。
+func (d *Dashboard) Close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return
+	}
+	d.closed = true
+	for ch := range d.clients {
+		close(ch)
+		delete(d.clients, ch)
+	}
+}
+
+func (d *Dashboard) publish(event string, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	msg := dashboardMessage{event: event, data: data}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return
+	}
+	if event != "hosts" {
+		d.backlog = append(d.backlog, msg)
+		if len(d.backlog) > dashboardBacklog {
+			d.backlog = d.backlog[len(d.backlog)-dashboardBacklog:]
+		}
+	}
+	for ch := range d.clients {
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
+
+func (d *Dashboard) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "ストリーミングに対応していません", http.StatusInternalServerError)
+		return
+	}
+
+	ch := make(chan dashboardMessage, dashboardClientBuffer)
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		http.Error(w, "終了しました", http.StatusServiceUnavailable)
+		return
+	}
+	hosts, _ := json.Marshal(d.hosts)
+	backlog := append([]dashboardMessage{{event: "hosts", data: hosts}}, d.backlog...)
+	d.clients[ch] = struct{}{}
+	d.mu.Unlock()
+
+	defer func() {
+		d.mu.Lock()
+		if _, ok := d.clients[ch]; ok {
+			delete(d.clients, ch)
+			close(ch)
+		}
+		d.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	for _, msg := range backlog {
+		writeSSE(w, msg)
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			writeSSE(w, msg)
+			flusher.Flush()
+		}
+	}
+}
+
+func writeSSE(w http.ResponseWriter, msg dashboardMessage) {
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", msg.event, msg.data)
+}
+
+func millis(seconds float64) float64 {
+	return seconds * 1000
+}
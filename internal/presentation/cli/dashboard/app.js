@@ -0,0 +1,146 @@
+"use strict";
+
+// 1ホストあたりグラフに残すRTTの数
+const chartPoints = 120;
+
+const lanes = [];
+
+function hostPanel(lane) {
+  return lanes[lane];
+}
+
+function resetHosts(hosts) {
+  const main = document.getElementById("hosts");
+  const template = document.getElementById("host-template");
+  main.replaceChildren();
+  lanes.length = 0;
+  hosts.forEach((host) => {
+    const section = template.content.firstElementChild.cloneNode(true);
+    section.querySelector(".name").textContent = host;
+    main.appendChild(section);
+    lanes.push({
+      stats: section.querySelector(".stats"),
+      chart: section.querySelector(".chart"),
+      art: section.querySelector(".art"),
+      lastSeq: -1,
+      rtts: [],
+    });
+  });
+}
+
+// addArtLine はシーケンスが巻き戻ったら新しい巡回としてアートを描き直します。
+function addArtLine(panel, seq, text, className) {
+  if (seq <= panel.lastSeq) {
+    panel.art.replaceChildren();
+  }
+  panel.lastSeq = seq;
+  const line = document.createElement("span");
+  line.className = className;
+  line.textContent = text + "\n";
+  panel.art.appendChild(line);
+}
+
+function addRtt(panel, rtt) {
+  panel.rtts.push(rtt);
+  if (panel.rtts.length > chartPoints) {
+    panel.rtts.shift();
+  }
+  drawChart(panel);
+}
+
+function drawChart(panel) {
+  const canvas = panel.chart;
+  const ctx = canvas.getContext("2d");
+  ctx.clearRect(0, 0, canvas.width, canvas.height);
+
+  const values = panel.rtts.filter((rtt) => rtt !== null);
+  if (values.length === 0) {
+    return;
+  }
+  const top = Math.max(...values) * 1.1 || 1;
+  const step = canvas.width / (chartPoints - 1);
+
+  ctx.fillStyle = "#888";
+  ctx.font = "10px sans-serif";
+  ctx.fillText(top.toFixed(1) + " ms", 4, 12);
+
+  ctx.strokeStyle = "#4cf";
+  ctx.beginPath();
+  let drawing = false;
+  panel.rtts.forEach((rtt, i) => {
+    const x = i * step;
+    if (rtt === null) {
+      // 応答のなかった点は線を途切れさせて赤い印を付ける
+      ctx.stroke();
+      ctx.fillStyle = "#e55";
+      ctx.fillRect(x - 1, canvas.height - 4, 3, 4);
+      ctx.beginPath();
+      drawing = false;
+      return;
+    }
+    const y = canvas.height - (rtt / top) * canvas.height;
+    if (drawing) {
+      ctx.lineTo(x, y);
+    } else {
+      ctx.moveTo(x, y);
+      drawing = true;
+    }
+  });
+  ctx.stroke();
+}
+
+const source = new EventSource("events");
+const status = document.getElementById("status");
+
+source.onopen = () => {
+  status.textContent = "受信中";
+};
+source.onerror = () => {
+  status.textContent = "切断されました";
+};
+
+source.addEventListener("hosts", (e) => {
+  resetHosts(JSON.parse(e.data) || []);
+});
+
+source.addEventListener("packet", (e) => {
+  const p = JSON.parse(e.data);
+  const panel = hostPanel(p.lane);
+  if (!panel) {
+    return;
+  }
+  addArtLine(panel, p.seq, p.artLine, p.level || "");
+  addRtt(panel, p.rttMs);
+});
+
+source.addEventListener("event", (e) => {
+  const ev = JSON.parse(e.data);
+  const panel = hostPanel(ev.lane);
+  if (!panel || (ev.type !== "timeout" && ev.type !== "lost")) {
+    return;
+  }
+  addArtLine(panel, ev.seq, ev.artLine, "missing");
+  addRtt(panel, null);
+});
+
+source.addEventListener("statistics", (e) => {
+  const s = JSON.parse(e.data);
+  const panel = hostPanel(s.lane);
+  if (!panel) {
+    return;
+  }
+  panel.stats.classList.remove("failure");
+  panel.stats.textContent =
+    `${s.sent}送信, ${s.recv}受信, ${s.loss.toFixed(1)}%ロス, ` +
+    `avg=${s.avgMs.toFixed(2)}ms p95=${s.p95Ms.toFixed(2)}ms jitter=${s.jitterMs.toFixed(2)}ms`;
+});
+
+source.addEventListener("failure", (e) => {
+  const f = JSON.parse(e.data);
+  const panel = hostPanel(f.lane);
+  if (!panel) {
+    return;
+  }
+  panel.stats.classList.add("failure");
+  panel.stats.textContent = f.error;
+});
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"nyagoPing/internal/domain/model"
+)
+
+func TestDashboard_ServeHTTP_Page(t *testing.T) {
+	dashboard := NewDashboard()
+	server := httptest.NewServer(dashboard)
+	defer server.Close()
+
+	for path, want := range map[string]string{
+		"/":       `<script src="app.js">`,
+		"/app.js": `new EventSource("events")`,
+	} {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("Get(%q) error = %v", path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Get(%q) status = %d", path, resp.StatusCode)
+		}
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Get(%q) に %q がありません", path, want)
+		}
+	}
+}
+
+func TestDashboard_ServeHTTP_Events(t *testing.T) {
+	dashboard := NewDashboard()
+	server := httptest.NewServer(dashboard)
+	defer server.Close()
+
+	dashboard.ShowPingStart([]string{"a.tld", "b.tld"})
+	// 接続前のパケットも後から開いたページに届く
+	dashboard.ShowPingPacket(0, model.NewPingPacket(0, 64, 64, net.IPv4(192, 0, 2, 1), 3*time.Millisecond, "line1"))
+
+	resp, err := http.Get(server.URL + "/events")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	dashboard.ShowPingEvent(1, model.NewPingEvent(model.PingEventTimeout, 0, "line1", nil))
+	dashboard.ShowHostStatistics(0, &model.PingStatistics{PacketsSent: 1, PacketsRecv: 1, AvgRtt: 3 * time.Millisecond})
+
+	want := []string{
+		"event: hosts",
+		`data: ["a.tld","b.tld"]`,
+		"event: packet",
+		`data: {"lane":0,"seq":0,"bytes":64,"ip":"192.0.2.1","ttl":64,"rttMs":3,"artLine":"line1"}`,
+		"event: event",
+		`data: {"lane":1,"type":"timeout","seq":0,"artLine":"line1"}`,
+		"event: statistics",
+		`data: {"lane":0,"sent":1,"recv":1,"loss":0,"avgMs":3,"p95Ms":0,"jitterMs":0}`,
+	}
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if scanner.Text() != "" {
+				lines <- scanner.Text()
+			}
+		}
+		close(lines)
+	}()
+	for i, w := range want {
+		select {
+		case got, ok := <-lines:
+			if !ok {
+				t.Fatalf("行 %d の前にストリームが終わりました", i)
+			}
+			if got != w {
+				t.Errorf("行 %d = %q, want %q", i, got, w)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("行 %d (%q) が届きません", i, w)
+		}
+	}
+
+	// Close で接続中のストリームが終わる
+	dashboard.Close()
+	select {
+	case <-lines:
+	case <-time.After(time.Second):
+		t.Error("Close() 後もストリームが終わりません")
+	}
+}
@@ -0,0 +1,334 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"nyagoPing/internal/domain/model"
+	"sync"
+	"time"
+)
+
+// jsonPacket は受信したパケットとイベントの共通の出力形式です。時間はナノ秒で書きます。
+type jsonPacket struct {
+	Type       string        `json:"type"`
+	Time       time.Time     `json:"time"`
+	Host       string        `json:"host,omitempty"`
+	Seq        int           `json:"seq"`
+	Bytes      int           `json:"bytes,omitempty"`
+	IP         string        `json:"ip,omitempty"`
+	TTL        int           `json:"ttl,omitempty"`
+	Rtt        time.Duration `json:"rtt,omitempty"`
+	ArtLine    string        `json:"artLine"`
+	StatusCode int           `json:"statusCode,omitempty"`
+	Level      string        `json:"level,omitempty"`
+	Error      string        `json:"error,omitempty"`
+}
+
+type jsonSummary struct {
+	Type       string          `json:"type"`
+	Time       time.Time       `json:"time"`
+	Host       string          `json:"host"`
+	Sent       int             `json:"sent"`
+	Recv       int             `json:"recv"`
+	Duplicates int             `json:"duplicates"`
+	Loss       float64         `json:"loss"`
+	MinRtt     time.Duration   `json:"minRtt"`
+	AvgRtt     time.Duration   `json:"avgRtt"`
+	MaxRtt     time.Duration   `json:"maxRtt"`
+	StdDevRtt  time.Duration   `json:"stdDevRtt"`
+	P50Rtt     time.Duration   `json:"p50Rtt"`
+	P90Rtt     time.Duration   `json:"p90Rtt"`
+	P95Rtt     time.Duration   `json:"p95Rtt"`
+	P99Rtt     time.Duration   `json:"p99Rtt"`
+	Jitter     time.Duration   `json:"jitter"`
+	Histogram  []jsonRttBucket `json:"histogram,omitempty"`
+}
+
+type jsonRttBucket struct {
+	Lower time.Duration `json:"lower"`
+	Upper time.Duration `json:"upper"`
+	Count int           `json:"count"`
+}
+
+// jsonDocument は --format json で最後に1つだけ書き出すホストごとの結果です。
+type jsonDocument struct {
+	Host       string        `json:"host"`
+	Packets    []*jsonPacket `json:"packets"`
+	Events     []*jsonPacket `json:"events"`
+	Statistics *jsonSummary  `json:"statistics"`
+}
+
+// recordTime は再生した記録なら記録時刻を、計測中なら現在時刻を返します。
+func recordTime(t time.Time) time.Time {
+	if t.IsZero() {
+		return time.Now()
+	}
+	return t
+}
+
+func newJSONPacket(host string, packet *model.PingPacket) *jsonPacket {
+	record := &jsonPacket{
+		Type:       "packet",
+		Time:       recordTime(packet.Time),
+		Host:       host,
+		Seq:        packet.Seq,
+		Bytes:      packet.Nbytes,
+		TTL:        packet.TTL,
+		Rtt:        packet.Rtt,
+		ArtLine:    packet.ArtLine,
+		StatusCode: packet.StatusCode,
+	}
+	if packet.IPAddr != nil {
+		record.IP = packet.IPAddr.String()
+	}
+	if packet.Level != model.LatencyUnknown {
+		record.Level = packet.Level.String()
+	}
+	return record
+}
+
+func newJSONEvent(host string, event *model.PingEvent) *jsonPacket {
+	record := &jsonPacket{
+		Type:       event.Type.String(),
+		Time:       recordTime(event.Time),
+		Host:       host,
+		Seq:        event.Seq,
+		Rtt:        event.Rtt,
+		ArtLine:    event.ArtLine,
+		StatusCode: event.StatusCode,
+	}
+	if event.Err != nil {
+		record.Error = event.Err.Error()
+	}
+	return record
+}
+
+func newJSONSummary(host string, stats *model.PingStatistics) *jsonSummary {
+	summary := &jsonSummary{
+		Type:       "summary",
+		Time:       recordTime(stats.Time),
+		Host:       host,
+		Sent:       stats.PacketsSent,
+		Recv:       stats.PacketsRecv,
+		Duplicates: stats.PacketsDuplicates,
+		Loss:       stats.PacketLoss,
+		MinRtt:     stats.MinRtt,
+		AvgRtt:     stats.AvgRtt,
+		MaxRtt:     stats.MaxRtt,
+		StdDevRtt:  stats.StdDevRtt,
+		P50Rtt:     stats.P50Rtt,
+		P90Rtt:     stats.P90Rtt,
+		P95Rtt:     stats.P95Rtt,
+		P99Rtt:     stats.P99Rtt,
+		Jitter:     stats.Jitter,
+	}
+	for _, b := range stats.Histogram {
+		summary.Histogram = append(summary.Histogram, jsonRttBucket{Lower: b.Lower, Upper: b.Upper, Count: b.Count})
+	}
+	return summary
+}
+
+// jsonOutput は stream が真なら記録を1行ずつ書き出し(NDJSON)、偽ならホストごとに溜めて最後にまとめて書き出します。
+// 書き込みに失敗した場合は最初のエラーを err に残し、以降は書き出しません。
+type jsonOutput struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	errOut  io.Writer
+	stream  bool
+	docs    []*jsonDocument
+	err     error
+}
+
+func newJSONOutput(out, errOut io.Writer, stream bool) *jsonOutput {
+	encoder := json.NewEncoder(out)
+	if !stream {
+		encoder.SetIndent("", "  ")
+	}
+	return &jsonOutput{
+		encoder: encoder,
+		errOut:  errOut,
+		stream:  stream,
+	}
+}
+
+// encode は v を書き出します。呼び出し側で mu を取っておく必要があります。
+func (o *jsonOutput) encode(v any) {
+	if o.err != nil {
+		return
+	}
+	if err := o.encoder.Encode(v); err != nil {
+		o.err = fmt.Errorf("出力エラー: %w", err)
+	}
+}
+
+// outputErr は書き込みに失敗していれば最初のエラーを返します。
+func (o *jsonOutput) outputErr() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.err
+}
+
+func (o *jsonOutput) start(hosts []string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.docs = make([]*jsonDocument, len(hosts))
+	for i, host := range hosts {
+		o.docs[i] = &jsonDocument{
+			Host:    host,
+			Packets: []*jsonPacket{},
+			Events:  []*jsonPacket{},
+		}
+	}
+}
+
+func (o *jsonOutput) doc(lane int) *jsonDocument {
+	for len(o.docs) <= lane {
+		o.docs = append(o.docs, &jsonDocument{Packets: []*jsonPacket{}, Events: []*jsonPacket{}})
+	}
+	return o.docs[lane]
+}
+
+func (o *jsonOutput) packet(lane int, record *jsonPacket) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.stream {
+		o.encode(record)
+		return
+	}
+	doc := o.doc(lane)
+	doc.Packets = append(doc.Packets, record)
+}
+
+func (o *jsonOutput) event(lane int, record *jsonPacket) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.stream {
+		o.encode(record)
+		return
+	}
+	doc := o.doc(lane)
+	doc.Events = append(doc.Events, record)
+}
+
+// finish は統計を書き出します。nil の統計は失敗したホストとして statistics を null にします。
+// multi が偽なら1ホスト分の文書をそのまま、真なら全ホストの配列を書き出します。
+func (o *jsonOutput) finish(stats []*model.PingStatistics, multi bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for lane, s := range stats {
+		doc := o.doc(lane)
+		if s == nil {
+			continue
+		}
+		if doc.Host == "" {
+			doc.Host = s.Addr
+		}
+		doc.Statistics = newJSONSummary(doc.Host, s)
+		if o.stream {
+			o.encode(doc.Statistics)
+		}
+	}
+	if o.stream {
+		return
+	}
+
+	if multi {
+		o.encode(o.docs)
+	} else if len(o.docs) > 0 {
+		o.encode(o.docs[0])
+	}
+}
+
+func (o *jsonOutput) host(lane int) string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if lane < len(o.docs) {
+		return o.docs[lane].Host
+	}
+	return ""
+}
+
+// JSONPresenter は1ホスト分の結果を JSON または NDJSON で標準出力に書き出します。
+// パケットにはホスト名を含めず、統計の host に対象のアドレスを書きます。
+type JSONPresenter struct {
+	output *jsonOutput
+}
+
+func NewJSONPresenter(out, errOut io.Writer, stream bool) *JSONPresenter {
+	return &JSONPresenter{
+		output: newJSONOutput(out, errOut, stream),
+	}
+}
+
+func (p *JSONPresenter) ShowPingPacket(packet *model.PingPacket) {
+	p.output.packet(0, newJSONPacket("", packet))
+}
+
+func (p *JSONPresenter) ShowPingEvent(event *model.PingEvent) {
+	p.output.event(0, newJSONEvent("", event))
+}
+
+func (p *JSONPresenter) ShowPingStatistics(stats *model.PingStatistics) {
+	p.output.finish([]*model.PingStatistics{stats}, false)
+}
+
+func (p *JSONPresenter) ShowASCIIArt(art *model.ASCIIArt) {
+	p.output.mu.Lock()
+	defer p.output.mu.Unlock()
+	p.output.encode(map[string]any{"type": "art", "lines": art.Lines()})
+}
+
+// ShowError は出力を解析する側を妨げないよう、エラーを標準エラー出力に書きます。
+func (p *JSONPresenter) ShowError(err error) {
+	p.output.mu.Lock()
+	defer p.output.mu.Unlock()
+	json.NewEncoder(p.output.errOut).Encode(map[string]string{"type": "error", "error": err.Error()})
+}
+
+// OutputErr は標準出力への書き込みに失敗していれば最初のエラーを返します。
+func (p *JSONPresenter) OutputErr() error {
+	return p.output.outputErr()
+}
+
+func (p *JSONPresenter) ShowVersion(appName, version string) {
+	p.output.mu.Lock()
+	defer p.output.mu.Unlock()
+	p.output.encode(map[string]string{"type": "version", "name": appName, "version": version})
+}
+
+// JSONMultiPresenter は複数ホストの結果を JSON または NDJSON で書き出します。各記録にはホスト名が入ります。
+type JSONMultiPresenter struct {
+	output *jsonOutput
+}
+
+func NewJSONMultiPresenter(out, errOut io.Writer, stream bool) *JSONMultiPresenter {
+	return &JSONMultiPresenter{
+		output: newJSONOutput(out, errOut, stream),
+	}
+}
+
+func (p *JSONMultiPresenter) ShowPingStart(hosts []string) {
+	p.output.start(hosts)
+}
+
+func (p *JSONMultiPresenter) ShowPingPacket(lane int, packet *model.PingPacket) {
+	p.output.packet(lane, newJSONPacket(p.output.host(lane), packet))
+}
+
+func (p *JSONMultiPresenter) ShowPingEvent(lane int, event *model.PingEvent) {
+	p.output.event(lane, newJSONEvent(p.output.host(lane), event))
+}
+
+func (p *JSONMultiPresenter) ShowPingStatistics(stats []*model.PingStatistics) {
+	p.output.finish(stats, true)
+}
+
+// OutputErr は標準出力への書き込みに失敗していれば最初のエラーを返します。
+func (p *JSONMultiPresenter) OutputErr() error {
+	return p.output.outputErr()
+}
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"nyagoPing/internal/domain/model"
+)
+
+func TestJSONPresenter_NDJSON(t *testing.T) {
+	var out, errOut bytes.Buffer
+	p := NewJSONPresenter(&out, &errOut, true)
+
+	p.ShowPingPacket(model.NewPingPacket(0, 64, 57, net.ParseIP("192.0.2.1"), 12*time.Millisecond, "line1"))
+	p.ShowPingEvent(model.NewPingEvent(model.PingEventTimeout, 1, "line2", nil))
+	p.ShowPingStatistics(model.CalculatePingStatistics("example.tld", 2, []time.Duration{12 * time.Millisecond}))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("出力行数 = %d, want 3: %s", len(lines), out.String())
+	}
+
+	var packet map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &packet); err != nil {
+		t.Fatalf("1行目の解析エラー: %v", err)
+	}
+	for key, want := range map[string]any{"type": "packet", "seq": 0.0, "bytes": 64.0, "ip": "192.0.2.1", "ttl": 57.0, "rtt": 12e6, "artLine": "line1"} {
+		if packet[key] != want {
+			t.Errorf("packet[%q] = %v, want %v", key, packet[key], want)
+		}
+	}
+	if _, ok := packet["time"]; !ok {
+		t.Error("packet に time がありません")
+	}
+
+	var event, summary map[string]any
+	json.Unmarshal([]byte(lines[1]), &event)
+	json.Unmarshal([]byte(lines[2]), &summary)
+	if event["type"] != "timeout" || event["seq"] != 1.0 {
+		t.Errorf("event = %v", event)
+	}
+	if summary["type"] != "summary" || summary["host"] != "example.tld" || summary["loss"] != 50.0 {
+		t.Errorf("summary = %v", summary)
+	}
+}
+
+func TestJSONMultiPresenter_JSON(t *testing.T) {
+	var out, errOut bytes.Buffer
+	p := NewJSONMultiPresenter(&out, &errOut, false)
+
+	p.ShowPingStart([]string{"a.tld", "b.tld"})
+	p.ShowPingPacket(1, model.NewPingPacket(0, 64, 57, net.ParseIP("192.0.2.2"), time.Millisecond, "line1"))
+	if out.Len() != 0 {
+		t.Fatalf("json 形式で統計の前に出力されました: %s", out.String())
+	}
+	p.ShowPingStatistics([]*model.PingStatistics{nil, model.CalculatePingStatistics("b.tld", 1, []time.Duration{time.Millisecond})})
+
+	var docs []jsonDocument
+	if err := json.Unmarshal(out.Bytes(), &docs); err != nil {
+		t.Fatalf("出力の解析エラー: %v\n%s", err, out.String())
+	}
+	if len(docs) != 2 {
+		t.Fatalf("文書数 = %d, want 2", len(docs))
+	}
+	if docs[0].Host != "a.tld" || docs[0].Statistics != nil || len(docs[0].Packets) != 0 {
+		t.Errorf("docs[0] = %+v, want 失敗したホスト", docs[0])
+	}
+	if docs[1].Host != "b.tld" || docs[1].Statistics == nil || len(docs[1].Packets) != 1 || docs[1].Packets[0].Host != "b.tld" {
+		t.Errorf("docs[1] = %+v", docs[1])
+	}
+}
+
+func TestJSONPresenter_ShowError(t *testing.T) {
+	var out, errOut bytes.Buffer
+	p := NewJSONPresenter(&out, &errOut, true)
+
+	p.ShowError(errors.New("nyago"))
+	if out.Len() != 0 || !strings.Contains(errOut.String(), `"error":"nyago"`) {
+		t.Errorf("stdout = %q, stderr = %q", out.String(), errOut.String())
+	}
+}
+
+func TestNewJSONEvent_StatusCode(t *testing.T) {
+	event := model.NewPingEvent(model.PingEventLost, 2, "line3", errors.New("ステータスコード 503"))
+	event.StatusCode = 503
+
+	b, err := json.Marshal(newJSONEvent("example.tld", event))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var record map[string]any
+	json.Unmarshal(b, &record)
+	if record["type"] != "lost" || record["statusCode"] != 503.0 || record["error"] != "ステータスコード 503" {
+		t.Errorf("event = %v", record)
+	}
+}
+
+func TestJSONPresenter_RecordedTime(t *testing.T) {
+	var out, errOut bytes.Buffer
+	p := NewJSONPresenter(&out, &errOut, true)
+
+	recorded := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	packet := model.NewPingPacket(0, 64, 57, net.ParseIP("192.0.2.1"), time.Millisecond, "line1")
+	packet.Time = recorded
+	event := model.NewPingEvent(model.PingEventTimeout, 1, "line2", nil)
+	event.Time = recorded.Add(time.Second)
+	stats := model.CalculatePingStatistics("example.tld", 2, []time.Duration{time.Millisecond})
+	stats.Time = recorded.Add(2 * time.Second)
+	p.ShowPingPacket(packet)
+	p.ShowPingEvent(event)
+	p.ShowPingStatistics(stats)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("出力行数 = %d, want 3: %s", len(lines), out.String())
+	}
+	// 再生した記録は再生した時刻ではなく記録時刻で書き出す
+	for i, want := range []time.Time{packet.Time, event.Time, stats.Time} {
+		var record struct {
+			Time time.Time `json:"time"`
+		}
+		if err := json.Unmarshal([]byte(lines[i]), &record); err != nil {
+			t.Fatalf("%d行目の解析エラー: %v", i+1, err)
+		}
+		if !record.Time.Equal(want) {
+			t.Errorf("%d行目の time = %v, want %v", i+1, record.Time, want)
+		}
+	}
+}
+
+// failingWriter は閉じたパイプのように常に書き込みに失敗します。
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestJSONPresenter_OutputErr(t *testing.T) {
+	var errOut bytes.Buffer
+	p := NewJSONPresenter(failingWriter{}, &errOut, true)
+	if err := p.OutputErr(); err != nil {
+		t.Fatalf("書き込む前の OutputErr() = %v", err)
+	}
+
+	p.ShowPingPacket(model.NewPingPacket(0, 64, 57, net.ParseIP("192.0.2.1"), time.Millisecond, "line1"))
+	p.ShowPingPacket(model.NewPingPacket(1, 64, 57, net.ParseIP("192.0.2.1"), time.Millisecond, "line2"))
+	if err := p.OutputErr(); err == nil || !strings.Contains(err.Error(), "broken pipe") {
+		t.Errorf("OutputErr() = %v, want 書き込みエラー", err)
+	}
+
+	m := NewJSONMultiPresenter(failingWriter{}, &errOut, false)
+	m.ShowPingStart([]string{"a.tld"})
+	m.ShowPingStatistics([]*model.PingStatistics{model.CalculatePingStatistics("a.tld", 1, []time.Duration{time.Millisecond})})
+	if err := m.OutputErr(); err == nil {
+		t.Error("JSONMultiPresenter の OutputErr() が nil です")
+	}
+}
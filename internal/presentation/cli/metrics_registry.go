@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"nyagoPing/internal/domain/model"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MetricsRegistry は監視中のホストごとの累計を保持し、Prometheus のテキスト形式で公開します。
+// 各メソッドは複数のゴルーチンから同時に呼び出せます。
+type MetricsRegistry struct {
+	mu      sync.Mutex
+	targets []*model.TargetMetrics
+}
+
+func NewMetricsRegistry(hosts []string) *MetricsRegistry {
+	targets := make([]*model.TargetMetrics, len(hosts))
+	for i, host := range hosts {
+		targets[i] = model.NewTargetMetrics(host, model.DefaultRttBuckets)
+	}
+	return &MetricsRegistry{
+		targets: targets,
+	}
+}
+
+func (r *MetricsRegistry) ObservePacket(lane int, packet *model.PingPacket) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if lane >= 0 && lane < len(r.targets) {
+		r.targets[lane].ObservePacket(packet)
+	}
+}
+
+func (r *MetricsRegistry) ObserveEvent(lane int, event *model.PingEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if lane >= 0 && lane < len(r.targets) {
+		r.targets[lane].ObserveEvent(event)
+	}
+}
+
+func (r *MetricsRegistry) ObserveFailure(lane int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if lane >= 0 && lane < len(r.targets) {
+		r.targets[lane].ObserveFailure()
+	}
+}
+
+func (r *MetricsRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.WriteTo(w)
+}
+
+type metricFamily struct {
+	name   string
+	help   string
+	kind   string
+	sample func(m *model.TargetMetrics) float64
+}
+
+var metricFamilies = []metricFamily{
+	{"nyagoping_up", "直近のプローブに応答があれば1、なければ0", "gauge", func(m *model.TargetMetrics) float64 { return boolValue(m.Up) }},
+	{"nyagoping_probes_total", "結果が確定したプローブの数(応答・ロス・送信失敗の合計)", "counter", func(m *model.TargetMetrics) float64 { return float64(m.Probes) }},
+	{"nyagoping_replies_total", "応答があったプローブの数", "counter", func(m *model.TargetMetrics) float64 { return float64(m.Replies) }},
+	{"nyagoping_lost_total", "タイムアウトまたはロスしたプローブの数", "counter", func(m *model.TargetMetrics) float64 { return float64(m.Lost) }},
+	{"nyagoping_duplicates_total", "重複して届いた応答の数", "counter", func(m *model.TargetMetrics) float64 { return float64(m.Duplicates) }},
+	{"nyagoping_send_errors_total", "送信に失敗した回数", "counter", func(m *model.TargetMetrics) float64 { return float64(m.SendErrors) }},
+	{"nyagoping_failures_total", "名前解決の失敗などで計測できなかった回数", "counter", func(m *model.TargetMetrics) float64 { return float64(m.Failures) }},
+	{"nyagoping_last_rtt_seconds", "直近に受信した応答のRTT", "gauge", func(m *model.TargetMetrics) float64 { return m.LastRtt.Seconds() }},
+}
+
+// WriteTo は全ホストのメトリクスを Prometheus のテキスト形式で書き出します。
+func (r *MetricsRegistry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var b strings.Builder
+	for _, family := range metricFamilies {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", family.name, family.help, family.name, family.kind)
+		for _, m := range r.targets {
+			fmt.Fprintf(&b, "%s{host=\"%s\"} %s\n", family.name, escapeLabel(m.Host), formatFloat(family.sample(m)))
+		}
+	}
+
+	b.WriteString("# HELP nyagoping_rtt_seconds 受信した応答のRTTの分布\n# TYPE nyagoping_rtt_seconds histogram\n")
+	for _, m := range r.targets {
+		host := escapeLabel(m.Host)
+		for i, upper := range m.RttBuckets {
+			fmt.Fprintf(&b, "nyagoping_rtt_seconds_bucket{host=\"%s\",le=\"%s\"} %d\n", host, formatFloat(upper.Seconds()), m.RttCounts[i])
+		}
+		fmt.Fprintf(&b, "nyagoping_rtt_seconds_bucket{host=\"%s\",le=\"+Inf\"} %d\n", host, m.Replies)
+		fmt.Fprintf(&b, "nyagoping_rtt_seconds_sum{host=\"%s\"} %s\n", host, formatFloat(m.RttSum.Seconds()))
+		fmt.Fprintf(&b, "nyagoping_rtt_seconds_count{host=\"%s\"} %d\n", host, m.Replies)
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"nyagoPing/internal/domain/model"
+)
+
+func TestMetricsRegistry_ServeHTTP(t *testing.T) {
+	registry := NewMetricsRegistry([]string{"a.tld", `b"tld`})
+	registry.ObservePacket(0, model.NewPingPacket(0, 64, 64, net.IPv4(192, 0, 2, 1), 3*time.Millisecond, "line1"))
+	registry.ObserveEvent(0, model.NewPingEvent(model.PingEventTimeout, 1, "line2", nil))
+	registry.ObserveFailure(1)
+
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	text := string(body)
+
+	for _, want := range []string{
+		"# TYPE nyagoping_up gauge\n",
+		`nyagoping_up{host="a.tld"} 0` + "\n",
+		`nyagoping_probes_total{host="a.tld"} 2` + "\n",
+		`nyagoping_lost_total{host="a.tld"} 1` + "\n",
+		`nyagoping_failures_total{host="b\"tld"} 1` + "\n",
+		`nyagoping_last_rtt_seconds{host="a.tld"} 0.003` + "\n",
+		"# TYPE nyagoping_rtt_seconds histogram\n",
+		`nyagoping_rtt_seconds_bucket{host="a.tld",le="0.0025"} 0` + "\n",
+		`nyagoping_rtt_seconds_bucket{host="a.tld",le="0.005"} 1` + "\n",
+		`nyagoping_rtt_seconds_bucket{host="a.tld",le="+Inf"} 1` + "\n",
+		`nyagoping_rtt_seconds_sum{host="a.tld"} 0.003` + "\n",
+		`nyagoping_rtt_seconds_count{host="a.tld"} 1` + "\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("出力に %q がありません\n%s", want, text)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"nyagoPing/internal/domain/model"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+const escPrevLines = "\x1b[%dF"
+
+// MTRPresenter はホップごとの集計表を巡ごとに描き直します。
+// 端末に出力する場合は前回の表をその場で書き換え、そうでなければ Finish で最後の表だけを出力します。
+type MTRPresenter struct {
+	out     io.Writer
+	inPlace bool
+	lines   int
+	last    string
+}
+
+func NewMTRPresenter(out io.Writer, inPlace bool) *MTRPresenter {
+	return &MTRPresenter{
+		out:     out,
+		inPlace: inPlace,
+	}
+}
+
+func (p *MTRPresenter) ShowMTRStart(target *model.PingTarget, config *model.TraceConfig) {
+	fmt.Fprintf(p.out, "MTR %s (%s) 最大%dホップ\n", target.Host(), target.IP(), config.MaxHops())
+}
+
+func (p *MTRPresenter) ShowMTRUpdate(route *model.RouteStatistics) {
+	table := formatMTRTable(route.Hops())
+	if !p.inPlace {
+		p.last = table
+		return
+	}
+	if p.lines > 0 {
+		fmt.Fprintf(p.out, escPrevLines, p.lines)
+	}
+	io.WriteString(p.out, escClearBelow+table)
+	p.lines = strings.Count(table, "\n")
+}
+
+// Finish は端末以外への出力で、最後の集計表を書き出します。
+func (p *MTRPresenter) Finish() {
+	if !p.inPlace && p.last != "" {
+		io.WriteString(p.out, p.last)
+	}
+}
+
+// formatMTRTable は「ホップ番号 アート行 応答元 ロス 送信数 Last Avg Best Wrst StDev」の表を返します。
+func formatMTRTable(hops []model.HopStatistics) string {
+	artWidth, hostWidth := 0, displayWidth("ホスト")
+	hosts := make([]string, len(hops))
+	for i, hop := range hops {
+		artWidth = max(artWidth, displayWidth(hop.ArtLine))
+		hosts[i] = "???"
+		if hop.Addr != nil {
+			hosts[i] = hop.Addr.String()
+			if hop.Name != "" && hop.Name != hosts[i] {
+				hosts[i] = fmt.Sprintf("%s (%s)", hop.Name, hosts[i])
+			}
+		}
+		hostWidth = max(hostWidth, displayWidth(hosts[i]))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, " # %s %s %s %s %7s %7s %7s %7s %7s\n",
+		padRight("", artWidth),
+		padRight("ホスト", hostWidth),
+		padLeft("ロス", 6),
+		padLeft("送信", 4),
+		"Last", "Avg", "Best", "Wrst", "StDev",
+	)
+	for i, hop := range hops {
+		loss := fmt.Sprintf("%5.1f%%", hop.Loss())
+		if hop.Recv < hop.Sent {
+			loss = color.New(color.FgRed).Sprint(loss)
+		}
+		art := padRight(hop.ArtLine, artWidth)
+		if hop.Recv == 0 {
+			art = color.New(color.Faint).Sprint(art)
+		}
+		fmt.Fprintf(&b, "%2d %s %s %s %4d %s %s %s %s %s\n",
+			hop.Hop,
+			art,
+			padRight(hosts[i], hostWidth),
+			loss,
+			hop.Sent,
+			mtrMillis(hop.Last, hop.Recv),
+			color.New(color.FgCyan, color.Bold).Sprint(mtrMillis(hop.Avg, hop.Recv)),
+			mtrMillis(hop.Best, hop.Recv),
+			mtrMillis(hop.Worst, hop.Recv),
+			mtrMillis(hop.StdDev, hop.Recv),
+		)
+	}
+	return b.String()
+}
+
+// mtrMillis は mtr と同じくミリ秒を小数1桁で表します。応答がなければ空欄にします。
+func mtrMillis(d time.Duration, recv int) string {
+	if recv == 0 {
+		return fmt.Sprintf("%7s", "-")
+	}
+	return fmt.Sprintf("%7.1f", float64(d)/float64(time.Millisecond))
+}
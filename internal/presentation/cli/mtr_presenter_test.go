@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"nyagoPing/internal/domain/model"
+)
+
+func newTestRoute() *model.RouteStatistics {
+	route := model.NewRouteStatistics()
+	route.Observe(model.NewTraceHop(1, &model.TraceReply{Addr: net.IPv4(198, 51, 100, 1), Rtt: 1500 * time.Microsecond}, "router1", "line1"))
+	route.Observe(model.NewTraceHop(2, &model.TraceReply{}, "", "line2"))
+	route.Observe(model.NewTraceHop(3, &model.TraceReply{Addr: net.IPv4(192, 0, 2, 1), Rtt: 12 * time.Millisecond, Reached: true}, "", "line3"))
+	return route
+}
+
+func TestFormatMTRTable(t *testing.T) {
+	table := formatMTRTable(newTestRoute().Hops())
+	lines := strings.Split(strings.TrimSuffix(table, "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("行数 = %d, want 4\n%s", len(lines), table)
+	}
+	for i, want := range []string{
+		" 1 line1 router1 (198.51.100.1)   0.0%    1     1.5     1.5     1.5     1.5     0.0",
+		" 2 line2 ???                    100.0%    1       -       -       -       -       -",
+		" 3 line3 192.0.2.1                0.0%    1    12.0    12.0    12.0    12.0     0.0",
+	} {
+		if lines[i+1] != want {
+			t.Errorf("行 %d = %q, want %q", i+1, lines[i+1], want)
+		}
+	}
+}
+
+func TestMTRPresenter(t *testing.T) {
+	route := newTestRoute()
+
+	var inPlace bytes.Buffer
+	p := NewMTRPresenter(&inPlace, true)
+	p.ShowMTRUpdate(route)
+	p.ShowMTRUpdate(route)
+	p.Finish()
+	// 2回目は前回の表の4行分だけ戻って描き直す
+	if got := strings.Count(inPlace.String(), "\x1b[4F"); got != 1 {
+		t.Errorf("カーソルを戻した回数 = %d, want 1\n%q", got, inPlace.String())
+	}
+
+	var piped bytes.Buffer
+	p = NewMTRPresenter(&piped, false)
+	p.ShowMTRUpdate(route)
+	p.ShowMTRUpdate(route)
+	if piped.Len() != 0 {
+		t.Errorf("Finish() 前に出力されました: %q", piped.String())
+	}
+	p.Finish()
+	if got, want := piped.String(), formatMTRTable(route.Hops()); got != want {
+		t.Errorf("Finish() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatMTRTable_NarrowArt(t *testing.T) {
+	route := model.NewRouteStatistics()
+	route.Observe(model.NewTraceHop(1, &model.TraceReply{Addr: net.IPv4(198, 51, 100, 1), Rtt: time.Millisecond}, "", "⣿⣿⡇"))
+	route.Observe(model.NewTraceHop(2, &model.TraceReply{Addr: net.IPv4(192, 0, 2, 1), Rtt: time.Millisecond, Reached: true}, "", "▀▄"))
+
+	lines := strings.Split(strings.TrimSuffix(formatMTRTable(route.Hops()), "\n"), "\n")
+	// 点字と半ブロックは1桁として詰められ、ホストの列が揃う
+	for i, want := range []string{
+		" 1 ⣿⣿⡇ 198.51.100.1",
+		" 2 ▀▄  192.0.2.1   ",
+	} {
+		if !strings.HasPrefix(lines[i+1], want) {
+			t.Errorf("%d行目 = %q, want prefix %q", i+1, lines[i+1], want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"fmt"
+	"nyagoPing/internal/domain/model"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	"golang.org/x/text/width"
+)
+
+var laneColors = []color.Attribute{
+	color.FgCyan,
+	color.FgMagenta,
+	color.FgYellow,
+	color.FgGreen,
+	color.FgBlue,
+	color.FgRed,
+}
+
+// MultiPingPresenter は複数ホストのPingの経過をホストの添字ごとに表示します。
+// 実装は複数のゴルーチンから同時に呼ばれても安全である必要があります。
+type MultiPingPresenter interface {
+	ShowPingStart(hosts []string)
+	ShowPingPacket(lane int, packet *model.PingPacket)
+	ShowPingEvent(lane int, event *model.PingEvent)
+	ShowPingStatistics(stats []*model.PingStatistics)
+}
+
+// MultiPresenter は複数ホストの応答をホストごとのレーンに分けて表示します。
+// 各メソッドは複数のゴルーチンから同時に呼ばれても行が混ざりません。
+type MultiPresenter struct {
+	mu         sync.Mutex
+	labels     []string
+	labelWidth int
+}
+
+func NewMultiPresenter() *MultiPresenter {
+	return &MultiPresenter{}
+}
+
+func (p *MultiPresenter) ShowPingStart(hosts []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.labelWidth = displayWidth("ホスト")
+	for _, host := range hosts {
+		if w := displayWidth(host); w > p.labelWidth {
+			p.labelWidth = w
+		}
+	}
+
+	p.labels = make([]string, len(hosts))
+	for i, host := range hosts {
+		p.labels[i] = color.New(laneColors[i%len(laneColors)], color.Bold).Sprint(padRight(host, p.labelWidth))
+	}
+}
+
+func (p *MultiPresenter) ShowPingPacket(lane int, packet *model.PingPacket) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fmt.Fprintf(color.Output, "%s │ %s%s %v\n",
+		p.label(lane),
+		colorArtLine(packet),
+		formatBytes(packet),
+		color.New(color.FgBlue, color.Bold).Sprint(packet.Rtt),
+	)
+}
+
+func (p *MultiPresenter) ShowPingEvent(lane int, event *model.PingEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fmt.Fprintf(color.Output, "%s │ %s\n", p.label(lane), formatEvent(event))
+}
+
+func (p *MultiPresenter) ShowPingStatistics(stats []*model.PingStatistics) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fmt.Fprintf(color.Output, "\n--- 統計 ---\n")
+	fmt.Fprintf(color.Output, "%s │ %s %s %s %12s %12s %12s %12s %12s\n",
+		padRight("ホスト", p.labelWidth),
+		padLeft("送信", 6),
+		padLeft("受信", 6),
+		padLeft("ロス", 7),
+		"min", "avg", "max", "p95", "jitter",
+	)
+	for lane := range p.labels {
+		if lane >= len(stats) || stats[lane] == nil {
+			fmt.Fprintf(color.Output, "%s │ %s\n", p.label(lane), color.New(color.FgRed).Sprint("失敗"))
+			continue
+		}
+		s := stats[lane]
+		fmt.Fprintf(color.Output, "%s │ %6d %6d %6.1f%% %12v %s %12v %12v %12v\n",
+			p.label(lane),
+			s.PacketsSent,
+			s.PacketsRecv,
+			s.PacketLoss,
+			s.MinRtt,
+			color.New(color.FgCyan, color.Bold).Sprintf("%12v", s.AvgRtt),
+			s.MaxRtt,
+			s.P95Rtt,
+			s.Jitter,
+		)
+	}
+}
+
+func (p *MultiPresenter) label(lane int) string {
+	if lane < 0 || lane >= len(p.labels) {
+		return padRight(fmt.Sprintf("#%d", lane), p.labelWidth)
+	}
+	return p.labels[lane]
+}
+
+// displayWidth は端末上の表示幅を返します。East Asian Width が Wide と Fullwidth の文字(漢字やかななど)を2桁、
+// それ以外を1桁とします。罫線・ブロック・点字のように曖昧(Ambiguous)や Neutral の文字は多くの端末に合わせて1桁です。
+func displayWidth(s string) int {
+	w := 0
+	for _, r := range s {
+		switch width.LookupRune(r).Kind() {
+		case width.EastAsianWide, width.EastAsianFullwidth:
+			w += 2
+		default:
+			w++
+		}
+	}
+	return w
+}
+
+func padRight(s string, cols int) string {
+	if w := displayWidth(s); w < cols {
+		return s + strings.Repeat(" ", cols-w)
+	}
+	return s
+}
+
+func padLeft(s string, cols int) string {
+	if w := displayWidth(s); w < cols {
+		return strings.Repeat(" ", cols-w) + s
+	}
+	return s
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"testing"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"nyago", 5},
+		{"ねこ", 4},
+		{"ＡＢ", 4},
+		{"猫ping", 6},
+		{"═╗", 2},
+		{"▀▄█", 3},
+		{"⣿⡇⠀", 3},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := displayWidth(tt.s); got != tt.want {
+			t.Errorf("displayWidth(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestPadRightPadLeft(t *testing.T) {
+	tests := []struct {
+		s         string
+		wantRight string
+		wantLeft  string
+	}{
+		{"⣿⣿", "⣿⣿  ", "  ⣿⣿"},
+		{"ねこ", "ねこ", "ねこ"},
+		{"a.tld", "a.tld", "a.tld"},
+		{"▀", "▀   ", "   ▀"},
+	}
+	for _, tt := range tests {
+		if got := padRight(tt.s, 4); got != tt.wantRight {
+			t.Errorf("padRight(%q, 4) = %q, want %q", tt.s, got, tt.wantRight)
+		}
+		if got := padLeft(tt.s, 4); got != tt.wantLeft {
+			t.Errorf("padLeft(%q, 4) = %q, want %q", tt.s, got, tt.wantLeft)
+		}
+	}
+}
@@ -3,10 +3,22 @@ package cli
 import (
 	"fmt"
 	"nyagoPing/internal/domain/model"
+	"nyagoPing/internal/domain/service"
+	"time"
 
 	"github.com/fatih/color"
 )
 
+// PingPresenter は1ホスト分のPingの経過と結果、およびアプリケーションのメッセージを表示します。
+type PingPresenter interface {
+	ShowPingPacket(packet *model.PingPacket)
+	ShowPingEvent(event *model.PingEvent)
+	ShowPingStatistics(stats *model.PingStatistics)
+	ShowASCIIArt(art *model.ASCIIArt)
+	ShowError(err error)
+	ShowVersion(appName, version string)
+}
+
 type Presenter struct{}
 
 func NewPresenter() *Presenter {
@@ -18,12 +30,80 @@ func (p *Presenter) ShowPingStart(host string, ip string) {
 }
 
 func (p *Presenter) ShowPingPacket(packet *model.PingPacket) {
-	fmt.Printf("%s %v\n",
-		packet.ArtLine,
+	fmt.Fprintf(color.Output, "%s%s %v\n",
+		colorArtLine(packet),
+		formatBytes(packet),
 		color.New(color.FgBlue, color.Bold).Sprint(packet.Rtt),
 	)
 }
 
+var levelColors = map[model.LatencyLevel]*color.Color{
+	model.LatencyGood: color.New(color.FgGreen),
+	model.LatencyWarn: color.New(color.FgYellow),
+	model.LatencyBad:  color.New(color.FgRed),
+}
+
+// levelMarker は色付きのアートで分類の色を示すため、行末に添える印です。
+const levelMarker = "●"
+
+// colorArtLine はパケットの分類に応じてアート行を色付けします。未分類の行はそのまま返します。
+// 色付きのアートは画像の色で描き、分類の色は行末の levelMarker で示します。
+func colorArtLine(packet *model.PingPacket) string {
+	if packet.ArtColors != nil {
+		line := paintCells(packet.ArtLine, packet.ArtColors, packet.ArtBackgrounds, detectColorProfile())
+		if c, ok := levelColors[packet.Level]; ok {
+			line += " " + c.Sprint(levelMarker)
+		}
+		return line
+	}
+	if c, ok := levelColors[packet.Level]; ok {
+		return c.Sprint(packet.ArtLine)
+	}
+	return packet.ArtLine
+}
+
+// formatBytes はサイズが分かるパケットだけ " 64B" のように表示します。
+// HTTPモードではステータスコードも併せて表示します。
+func formatBytes(packet *model.PingPacket) string {
+	var s string
+	if packet.StatusCode != 0 {
+		s += fmt.Sprintf(" %d", packet.StatusCode)
+	}
+	if packet.Nbytes > 0 {
+		s += fmt.Sprintf(" %dB", packet.Nbytes)
+	}
+	return s
+}
+
+func (p *Presenter) ShowPingEvent(event *model.PingEvent) {
+	fmt.Fprintln(color.Output, formatEvent(event))
+}
+
+// formatEvent は応答のなかったシーケンスのアート行を薄く描き、理由を添えます。
+// 送信エラーと重複応答はアート行を描かず、そのシーケンスの注記だけを表示します。
+func formatEvent(event *model.PingEvent) string {
+	switch event.Type {
+	case model.PingEventTimeout:
+		return fmt.Sprintf("%s %s",
+			color.New(color.Faint).Sprint(event.ArtLine),
+			color.New(color.FgRed, color.Bold).Sprint("× タイムアウト"),
+		)
+	case model.PingEventLost:
+		reason := "× ロス"
+		if event.Err != nil {
+			reason = fmt.Sprintf("× ロス (%v)", event.Err)
+		}
+		return fmt.Sprintf("%s %s",
+			color.New(color.Faint).Sprint(event.ArtLine),
+			color.New(color.FgRed, color.Bold).Sprint(reason),
+		)
+	case model.PingEventSendError:
+		return color.New(color.FgYellow).Sprintf("! seq=%d 送信エラー: %v", event.Seq, event.Err)
+	default:
+		return color.New(color.FgYellow).Sprintf("DUP! seq=%d %v", event.Seq, event.Rtt)
+	}
+}
+
 func (p *Presenter) ShowPingStatistics(stats *model.PingStatistics) {
 	fmt.Fprintf(color.Output, "\n--- %s 統計 ---\n", stats.Addr)
 	fmt.Fprintf(color.Output, "%d送信, %d受信, %.1f%%ロス, avg=%v\n",
@@ -32,11 +112,59 @@ func (p *Presenter) ShowPingStatistics(stats *model.PingStatistics) {
 		stats.PacketLoss,
 		color.New(color.FgCyan, color.Bold).Sprint(stats.AvgRtt),
 	)
+	if stats.PacketsRecv == 0 {
+		return
+	}
+	fmt.Fprintf(color.Output, "min=%v max=%v stddev=%v jitter=%v\n",
+		stats.MinRtt, stats.MaxRtt, stats.StdDevRtt, stats.Jitter)
+	fmt.Fprintf(color.Output, "p50=%v p90=%v p95=%v p99=%v\n",
+		stats.P50Rtt, stats.P90Rtt, stats.P95Rtt, stats.P99Rtt)
+	showHistogram(stats.Histogram)
+}
+
+const histogramBarWidth = 40
+
+// showHistogram は RTT の区間ごとの件数をアート文字の棒グラフで表示します。
+func showHistogram(histogram []model.RttBucket) {
+	if len(histogram) == 0 {
+		return
+	}
+
+	maxCount := 0
+	labels := make([]string, len(histogram))
+	labelWidth := 0
+	for i, bucket := range histogram {
+		maxCount = max(maxCount, bucket.Count)
+		labels[i] = fmt.Sprintf("%v - %v", roundRtt(bucket.Lower), roundRtt(bucket.Upper))
+		labelWidth = max(labelWidth, displayWidth(labels[i]))
+	}
+
+	fmt.Fprintln(color.Output)
+	for i, bucket := range histogram {
+		fmt.Fprintf(color.Output, "%s │%s %d\n",
+			padLeft(labels[i], labelWidth),
+			color.New(color.FgCyan).Sprint(service.HistogramBar(bucket.Count, maxCount, histogramBarWidth)),
+			bucket.Count,
+		)
+	}
+}
+
+// roundRtt はヒストグラムの区間表示が長くなりすぎないよう有効桁を揃えます。
+func roundRtt(rtt time.Duration) time.Duration {
+	switch {
+	case rtt >= time.Second:
+		return rtt.Round(time.Millisecond)
+	case rtt >= time.Millisecond:
+		return rtt.Round(10 * time.Microsecond)
+	default:
+		return rtt.Round(time.Microsecond)
+	}
 }
 
 func (p *Presenter) ShowASCIIArt(art *model.ASCIIArt) {
-	for _, line := range art.Lines() {
-		fmt.Println(line)
+	profile := detectColorProfile()
+	for i, line := range art.Lines() {
+		fmt.Fprintln(color.Output, paintCells(line, art.GetColors(i), art.GetBackgrounds(i), profile))
 	}
 }
 
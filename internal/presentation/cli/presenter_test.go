@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"nyagoPing/internal/domain/model"
+
+	"github.com/fatih/color"
+)
+
+func TestColorArtLine(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	t.Cleanup(func() { color.NoColor = noColor })
+	t.Setenv("COLORTERM", "truecolor")
+
+	green := levelColors[model.LatencyGood].Sprint(levelMarker)
+	tests := []struct {
+		name   string
+		colors []model.RGB
+		level  model.LatencyLevel
+		want   string
+	}{
+		{"分類のみ", nil, model.LatencyGood, levelColors[model.LatencyGood].Sprint("ねこ")},
+		{"未分類", nil, model.LatencyUnknown, "ねこ"},
+		{"色付きのアートは印で分類を示す", []model.RGB{{R: 255}, {R: 255}}, model.LatencyGood, "\x1b[38;2;255;0;0mねこ\x1b[0m " + green},
+		{"色付きのアートで未分類", []model.RGB{{R: 255}, {R: 255}}, model.LatencyUnknown, "\x1b[38;2;255;0;0mねこ\x1b[0m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packet := model.NewPingPacket(0, 64, 57, net.ParseIP("192.0.2.1"), time.Millisecond, "ねこ")
+			packet.ArtColors = tt.colors
+			packet.Level = tt.level
+			if got := colorArtLine(packet); got != tt.want {
+				t.Errorf("colorArtLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+}
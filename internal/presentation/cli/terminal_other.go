@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+
+package cli
+
+// enableKeyInput はこの環境では端末の設定を変えません。キーは Enter を押した後に届きます。
+func enableKeyInput(fd int) (func(), error) {
+	return func() {}, nil
+}
+
+// terminalSize はこの環境では端末の大きさを取得できません。
+func terminalSize(fd int) (width, height int, ok bool) {
+	return 0, 0, false
+}
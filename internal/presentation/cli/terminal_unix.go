@@ -0,0 +1,35 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package cli
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// enableKeyInput は fd の行バッファとエコーを止め、キーを1文字ずつ読めるようにします。
+// Ctrl+C のシグナルは止めません。戻り値の関数で元の設定に戻します。
+func enableKeyInput(fd int) (func(), error) {
+	saved, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return nil, err
+	}
+	raw := *saved
+	raw.Lflag &^= unix.ICANON | unix.ECHO
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, ioctlSetTermios, &raw); err != nil {
+		return nil, err
+	}
+	return func() {
+		unix.IoctlSetTermios(fd, ioctlSetTermios, saved)
+	}, nil
+}
+
+// terminalSize は fd の端末の幅と高さを返します。端末でなければ ok は false です。
+func terminalSize(fd int) (width, height int, ok bool) {
+	ws, err := unix.IoctlGetWinsize(fd, unix.TIOCGWINSZ)
+	if err != nil || ws.Col == 0 || ws.Row == 0 {
+		return 0, 0, false
+	}
+	return int(ws.Col), int(ws.Row), true
+}
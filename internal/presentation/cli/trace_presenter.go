@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"fmt"
+	"nyagoPing/internal/domain/model"
+
+	"github.com/fatih/color"
+)
+
+// TracePresenter は経路の調査をホップごとのアート行で表示します。
+type TracePresenter interface {
+	ShowTraceStart(target *model.PingTarget, config *model.TraceConfig)
+	ShowTraceHop(hop *model.TraceHop)
+}
+
+func (p *Presenter) ShowTraceStart(target *model.PingTarget, config *model.TraceConfig) {
+	fmt.Printf("TRACE %s (%s) 最大%dホップ\n", target.Host(), target.IP(), config.MaxHops())
+}
+
+func (p *Presenter) ShowTraceHop(hop *model.TraceHop) {
+	fmt.Fprintln(color.Output, formatTraceHop(hop))
+}
+
+// formatTraceHop は「ホップ番号 アート行 応答元 (逆引き名) RTT」の1行を返します。
+// 応答のなかったホップはアート行を薄く描きます。
+func formatTraceHop(hop *model.TraceHop) string {
+	number := fmt.Sprintf("%2d", hop.Hop)
+	if hop.IsTimeout() {
+		return fmt.Sprintf("%s %s %s",
+			number,
+			color.New(color.Faint).Sprint(hop.ArtLine),
+			color.New(color.FgRed, color.Bold).Sprint("*"),
+		)
+	}
+
+	addr := hop.Addr.String()
+	if hop.Name != "" && hop.Name != addr {
+		addr = fmt.Sprintf("%s (%s)", hop.Name, addr)
+	}
+	line := fmt.Sprintf("%s %s %s %v",
+		number,
+		hop.ArtLine,
+		color.New(color.Bold).Sprint(addr),
+		color.New(color.FgBlue, color.Bold).Sprint(hop.Rtt),
+	)
+	if hop.Unreachable {
+		line += color.New(color.FgRed, color.Bold).Sprint(" !到達不能")
+	}
+	return line
+}
@@ -0,0 +1,244 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"nyagoPing/internal/domain/model"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+const (
+	// tuiHistory はスパークライン用に残す直近のRTTの数です。端末の幅がこれより広くても足ります。
+	tuiHistory = 512
+	// tuiChromeRows はアート以外に使う行数(見出し・スパークライン・ステータスバー)です。
+	tuiChromeRows = 3
+	// tuiDefaultWidth と tuiDefaultHeight は端末の大きさが分からないときに使います。
+	tuiDefaultWidth  = 80
+	tuiDefaultHeight = 24
+	// tuiMissing はスパークラインで応答のなかったシーケンスを表します。
+	tuiMissing time.Duration = -1
+)
+
+const (
+	escEnterScreen = "\x1b[?1049h\x1b[?25l\x1b[?7l"
+	escLeaveScreen = "\x1b[?7h\x1b[?25h\x1b[?1049l"
+	escHome        = "\x1b[H"
+	escClearLine   = "\x1b[K"
+	escClearBelow  = "\x1b[J"
+)
+
+var sparkRunes = []rune("▁▂▃▄▅▆▇█")
+
+// TUIPresenter は代替スクリーンにアート・スパークライン・ステータスバーを描き、応答のたびにその場で描き直します。
+// Start から Stop までの間だけ画面を占有し、Stop 後の最終統計は final に表示させます。
+type TUIPresenter struct {
+	mu     sync.Mutex
+	out    io.Writer
+	final  PingPresenter
+	size   func() (width, height int)
+	host   string
+	lines  []string
+	rtts   []time.Duration
+	recv   []time.Duration
+	sent   int
+	dups   int
+	notice string
+	stats  *model.PingStatistics
+	active bool
+}
+
+// NewTUIPresenter は out に描画する TUIPresenter を作ります。size には端末の大きさを返す関数を渡します。
+func NewTUIPresenter(out io.Writer, final PingPresenter, host string, size func() (int, int)) *TUIPresenter {
+	return &TUIPresenter{
+		out:   out,
+		final: final,
+		size:  size,
+		host:  host,
+	}
+}
+
+// Start は代替スクリーンに切り替えて最初の画面を描きます。
+func (p *TUIPresenter) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.active = true
+	io.WriteString(p.out, escEnterScreen)
+	p.redraw()
+}
+
+// Stop は元の画面に戻し、最終統計があれば final で表示します。
+func (p *TUIPresenter) Stop() {
+	p.mu.Lock()
+	if !p.active {
+		p.mu.Unlock()
+		return
+	}
+	p.active = false
+	io.WriteString(p.out, escLeaveScreen)
+	stats := p.stats
+	p.mu.Unlock()
+
+	if stats != nil {
+		p.final.ShowPingStatistics(stats)
+	}
+}
+
+func (p *TUIPresenter) ShowPingPacket(packet *model.PingPacket) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sent++
+	p.setLine(packet.Seq, colorArtLine(packet))
+	p.pushRtt(packet.Rtt)
+	p.recv = append(p.recv, packet.Rtt)
+	p.redraw()
+}
+
+func (p *TUIPresenter) ShowPingEvent(event *model.PingEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch {
+	case event.IsMissing():
+		p.sent++
+		p.setLine(event.Seq, formatEvent(event))
+		p.pushRtt(tuiMissing)
+	case event.Type == model.PingEventDuplicate:
+		p.dups++
+		p.notice = formatEvent(event)
+	default:
+		p.notice = formatEvent(event)
+	}
+	p.redraw()
+}
+
+func (p *TUIPresenter) ShowPingStatistics(stats *model.PingStatistics) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stats = stats
+	p.redraw()
+}
+
+func (p *TUIPresenter) ShowASCIIArt(art *model.ASCIIArt) {
+	p.final.ShowASCIIArt(art)
+}
+
+func (p *TUIPresenter) ShowError(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.notice = color.New(color.FgRed, color.Bold).Sprintf("ERROR %v", err)
+	p.redraw()
+}
+
+func (p *TUIPresenter) ShowVersion(appName, version string) {
+	p.final.ShowVersion(appName, version)
+}
+
+// setLine はシーケンス番号の位置にアート行を置きます。応答の順序が前後しても行の位置は変わりません。
+func (p *TUIPresenter) setLine(seq int, line string) {
+	for len(p.lines) <= seq {
+		p.lines = append(p.lines, "")
+	}
+	p.lines[seq] = line
+}
+
+func (p *TUIPresenter) pushRtt(rtt time.Duration) {
+	p.rtts = append(p.rtts, rtt)
+	if len(p.rtts) > tuiHistory {
+		p.rtts = p.rtts[len(p.rtts)-tuiHistory:]
+	}
+}
+
+func (p *TUIPresenter) redraw() {
+	if !p.active {
+		return
+	}
+	io.WriteString(p.out, p.frame())
+}
+
+// frame は画面全体を描き直すエスケープシーケンスを返します。
+// アートが画面に収まらない場合は最新の行が見えるよう末尾を表示します。
+func (p *TUIPresenter) frame() string {
+	width, height := p.size()
+	artRows := max(height-tuiChromeRows, 1)
+
+	var b strings.Builder
+	b.WriteString(escHome)
+
+	header := color.New(color.Bold).Sprintf("PING %s", p.host)
+	if p.notice != "" {
+		header += "  " + p.notice
+	}
+	b.WriteString(header + escClearLine + "\n")
+
+	lines := p.lines
+	if len(lines) > artRows {
+		lines = lines[len(lines)-artRows:]
+	}
+	for i := 0; i < artRows; i++ {
+		if i < len(lines) {
+			b.WriteString(lines[i])
+		}
+		b.WriteString(escClearLine + "\n")
+	}
+
+	b.WriteString("RTT " + sparkline(p.rtts, max(width-4, 1)) + escClearLine + "\n")
+	b.WriteString(color.New(color.ReverseVideo).Sprint(padRight(p.status(), width)))
+	b.WriteString(escClearBelow)
+	return b.String()
+}
+
+// status はステータスバーの文言です。最終統計が届く前は受信済みのRTTから計算します。
+func (p *TUIPresenter) status() string {
+	stats := p.stats
+	state := "q:停止"
+	if stats == nil {
+		stats = model.CalculatePingStatistics(p.host, p.sent, p.recv)
+	} else {
+		state = "完了"
+	}
+
+	s := fmt.Sprintf(" %d送信 %d受信 %.1f%%ロス", stats.PacketsSent, stats.PacketsRecv, stats.PacketLoss)
+	if p.dups > 0 {
+		s += fmt.Sprintf(" %dDUP", p.dups)
+	}
+	if stats.PacketsRecv > 0 {
+		s += fmt.Sprintf(" │ last=%v avg=%v min=%v max=%v jitter=%v",
+			roundRtt(p.recv[len(p.recv)-1]), roundRtt(stats.AvgRtt), roundRtt(stats.MinRtt), roundRtt(stats.MaxRtt), roundRtt(stats.Jitter))
+	}
+	return s + " │ " + state + " "
+}
+
+// sparkline は直近 width 件のRTTを最小から最大までの8段階の棒で表します。応答のなかった位置は × にします。
+func sparkline(rtts []time.Duration, width int) string {
+	if len(rtts) > width {
+		rtts = rtts[len(rtts)-width:]
+	}
+
+	lo, hi := time.Duration(-1), time.Duration(0)
+	for _, rtt := range rtts {
+		if rtt == tuiMissing {
+			continue
+		}
+		if lo < 0 || rtt < lo {
+			lo = rtt
+		}
+		hi = max(hi, rtt)
+	}
+
+	var b strings.Builder
+	for _, rtt := range rtts {
+		if rtt == tuiMissing {
+			b.WriteString(color.New(color.FgRed).Sprint("×"))
+			continue
+		}
+		level := 0
+		if hi > lo {
+			level = int(float64(rtt-lo) / float64(hi-lo) * float64(len(sparkRunes)-1))
+		}
+		b.WriteRune(sparkRunes[level])
+	}
+	return b.String()
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"nyagoPing/internal/domain/model"
+)
+
+type recordingPresenter struct {
+	Presenter
+	stats *model.PingStatistics
+}
+
+func (p *recordingPresenter) ShowPingStatistics(stats *model.PingStatistics) {
+	p.stats = stats
+}
+
+func TestTUIPresenter(t *testing.T) {
+	var out bytes.Buffer
+	final := &recordingPresenter{}
+	tui := NewTUIPresenter(&out, final, "a.tld", func() (int, int) { return 60, 5 })
+
+	tui.Start()
+	if !strings.HasPrefix(out.String(), escEnterScreen) {
+		t.Errorf("Start() で代替スクリーンに切り替わっていません: %q", out.String())
+	}
+
+	ip := net.IPv4(192, 0, 2, 1)
+	tui.ShowPingPacket(model.NewPingPacket(0, 64, 64, ip, 10*time.Millisecond, "line1"))
+	tui.ShowPingEvent(model.NewPingEvent(model.PingEventTimeout, 1, "line2", nil))
+	tui.ShowPingPacket(model.NewPingPacket(2, 64, 64, ip, 30*time.Millisecond, "line3"))
+	tui.ShowPingPacket(model.NewPingPacket(3, 64, 64, ip, 20*time.Millisecond, "line4"))
+
+	frame := tui.frame()
+	// 高さ5から見出し・スパークライン・ステータスバーを除いた2行に最新のアートが収まる
+	for _, want := range []string{"PING a.tld", "line3", "line4", "RTT ▁×█▄", "4送信 3受信 25.0%ロス", "last=20ms", "q:停止"} {
+		if !strings.Contains(frame, want) {
+			t.Errorf("画面に %q がありません\n%s", want, frame)
+		}
+	}
+	if strings.Contains(frame, "line1") {
+		t.Errorf("画面に収まらない行が描かれています\n%s", frame)
+	}
+
+	stats := model.CalculatePingStatistics("192.0.2.1", 4, []time.Duration{10 * time.Millisecond, 30 * time.Millisecond, 20 * time.Millisecond})
+	tui.ShowPingStatistics(stats)
+	if frame := tui.frame(); !strings.Contains(frame, "完了") {
+		t.Errorf("最終統計の後も完了と表示されていません\n%s", frame)
+	}
+
+	tui.Stop()
+	if !strings.HasSuffix(out.String(), escLeaveScreen) {
+		t.Error("Stop() で元の画面に戻っていません")
+	}
+	if final.stats != stats {
+		t.Error("Stop() で最終統計が元の画面に表示されていません")
+	}
+
+	// Stop 後の呼び出しは画面に描かない
+	n := out.Len()
+	tui.ShowPingPacket(model.NewPingPacket(4, 64, 64, ip, time.Millisecond, "line5"))
+	if out.Len() != n {
+		t.Error("Stop() 後に画面を描き直しました")
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name  string
+		rtts  []time.Duration
+		width int
+		want  string
+	}{
+		{"空", nil, 10, ""},
+		{"同じ値", []time.Duration{5, 5, 5}, 10, "▁▁▁"},
+		{"最小から最大", []time.Duration{0, 7, 14}, 10, "▁▄█"},
+		{"幅で切り詰め", []time.Duration{100, 0, 7, 14}, 3, "▁▄█"},
+		{"ロス", []time.Duration{0, tuiMissing, 7}, 10, "▁×█"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sparkline(tt.rtts, tt.width); got != tt.want {
+				t.Errorf("sparkline() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
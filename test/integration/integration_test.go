@@ -1,17 +1,22 @@
 package integration
 
 import (
+	"context"
 	"image"
 	"image/color"
 	"image/png"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 
 	"nyagoPing/internal/application/usecase"
 	"nyagoPing/internal/domain/model"
+	"nyagoPing/internal/domain/repository"
 	"nyagoPing/internal/domain/service"
 	"nyagoPing/internal/infrastructure/persistence"
+	"nyagoPing/internal/infrastructure/ping"
 )
 
 func TestGenerateASCIIArtUseCase_Integration(t *testing.T) {
@@ -89,3 +94,174 @@ func TestPingUseCase_AutoCount_Integration(t *testing.T) {
 		t.Errorf("CalculateOptimalCount() = %v, want 3", expectedCount)
 	}
 }
+
+func TestPingUseCase_Simulated_Integration(t *testing.T) {
+	tmpDir := t.TempDir()
+	artPath := filepath.Join(tmpDir, "test_art.txt")
+	scenarioPath := filepath.Join(tmpDir, "scenario.json")
+
+	art, _ := model.NewASCIIArt([]string{"line1", "line2", "line3", "line4", "line5", "line6"})
+	asciiRepo := persistence.NewFileASCIIArtRepository()
+	if err := asciiRepo.Save(artPath, art); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	scenario := `{
+  "seed": 7,
+  "rtt": {"distribution": "uniform", "mean": "2ms", "jitter": "1ms"},
+  "lossBursts": [{"start": 1, "length": 2}]
+}`
+	if err := os.WriteFile(scenarioPath, []byte(scenario), 0644); err != nil {
+		t.Fatalf("シナリオファイルの作成エラー: %v", err)
+	}
+
+	pingRepo := ping.NewProtocolRepository(map[model.Protocol]repository.PingRepository{
+		model.ProtocolSimulated: ping.NewSimulatedRepository(),
+	})
+	uc := usecase.NewPingUseCase(pingRepo, asciiRepo, persistence.NewFileScenarioRepository(), persistence.NewFileSessionRepository(), persistence.NewCSVSessionExporter(), persistence.NewFileThresholdRepository(), service.NewASCIIArtGenerator())
+
+	input := &usecase.PingInput{
+		Host:           "example.tld",
+		Interval:       time.Millisecond,
+		ASCIIArtPath:   artPath,
+		AutoCountByArt: true,
+		ScenarioPath:   scenarioPath,
+	}
+
+	var lines []string
+	var missing []string
+	var stats *model.PingStatistics
+	err := uc.Execute(context.Background(), input,
+		func(packet *model.PingPacket) {
+			lines = append(lines, packet.ArtLine)
+		},
+		func(event *model.PingEvent) {
+			if event.IsMissing() {
+				missing = append(missing, event.ArtLine)
+			}
+		},
+		func(s *model.PingStatistics) {
+			stats = s
+		},
+	)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := []string{"line1", "line4", "line5", "line6"}
+	if len(lines) != len(want) {
+		t.Fatalf("描画された行 = %v, want %v", lines, want)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line[%d] = %v, want %v", i, lines[i], want[i])
+		}
+	}
+	if len(missing) != 2 || missing[0] != "line2" || missing[1] != "line3" {
+		t.Errorf("欠けた行 = %v, want [line2 line3]", missing)
+	}
+
+	if stats == nil {
+		t.Fatal("統計が通知されませんでした")
+	}
+	if stats.PacketsSent != 6 || stats.PacketsRecv != 4 {
+		t.Errorf("stats = %+v, want 6送信 4受信", stats)
+	}
+	if stats.MinRtt < time.Millisecond || stats.MaxRtt > 3*time.Millisecond {
+		t.Errorf("RTT範囲 = %v-%v, want 1ms-3ms", stats.MinRtt, stats.MaxRtt)
+	}
+}
+
+func TestRecordReplay_Integration(t *testing.T) {
+	tmpDir := t.TempDir()
+	artPath := filepath.Join(tmpDir, "test_art.txt")
+	sessionPath := filepath.Join(tmpDir, "session.jsonl")
+
+	art, _ := model.NewASCIIArt([]string{"line1", "line2", "line3", "line4"})
+	asciiRepo := persistence.NewFileASCIIArtRepository()
+	if err := asciiRepo.Save(artPath, art); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	pingRepo := ping.NewProtocolRepository(map[model.Protocol]repository.PingRepository{
+		model.ProtocolSimulated: ping.NewSimulatedRepository(),
+	})
+	sessionRepo := persistence.NewFileSessionRepository()
+	pingUseCase := usecase.NewPingUseCase(pingRepo, asciiRepo, persistence.NewFileScenarioRepository(), sessionRepo, persistence.NewCSVSessionExporter(), persistence.NewFileThresholdRepository(), service.NewASCIIArtGenerator())
+
+	input := &usecase.PingInput{
+		Host:           "example.tld",
+		Interval:       time.Millisecond,
+		ASCIIArtPath:   artPath,
+		AutoCountByArt: true,
+		Simulate:       true,
+		Seed:           11,
+		RecordPath:     sessionPath,
+	}
+
+	var recorded []*model.PingPacket
+	var recordedEvents []*model.PingEvent
+	var recordedStats *model.PingStatistics
+	err := pingUseCase.Execute(context.Background(), input,
+		func(packet *model.PingPacket) {
+			recorded = append(recorded, packet)
+		},
+		func(event *model.PingEvent) {
+			recordedEvents = append(recordedEvents, event)
+		},
+		func(s *model.PingStatistics) {
+			recordedStats = s
+		},
+	)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	replayUseCase := usecase.NewReplayUseCase(sessionRepo, persistence.NewFileThresholdRepository())
+
+	var hosts []string
+	var replayed []*model.PingPacket
+	var replayedEvents []*model.PingEvent
+	var replayedStats *model.PingStatistics
+	err = replayUseCase.Execute(context.Background(), &usecase.ReplayInput{SessionPath: sessionPath, Speed: 0},
+		func(h []string) {
+			hosts = h
+		},
+		func(lane int, packet *model.PingPacket) {
+			if lane != 0 {
+				t.Errorf("lane = %d, want 0", lane)
+			}
+			replayed = append(replayed, packet)
+		},
+		func(lane int, event *model.PingEvent) {
+			replayedEvents = append(replayedEvents, event)
+		},
+		func(lane int, s *model.PingStatistics) {
+			replayedStats = s
+		},
+	)
+	if err != nil {
+		t.Fatalf("Replay Execute() error = %v", err)
+	}
+
+	if len(hosts) != 1 || hosts[0] != "example.tld" {
+		t.Errorf("hosts = %v, want [example.tld]", hosts)
+	}
+	if len(replayed) != len(recorded) || len(replayedEvents) != len(recordedEvents) {
+		t.Fatalf("再生数 = %d+%d, want %d+%d", len(replayed), len(replayedEvents), len(recorded), len(recordedEvents))
+	}
+	for i := range recorded {
+		if replayed[i].Seq != recorded[i].Seq || replayed[i].Rtt != recorded[i].Rtt || replayed[i].ArtLine != recorded[i].ArtLine {
+			t.Errorf("replayed[%d] = %+v, want %+v", i, replayed[i], recorded[i])
+		}
+	}
+	if replayedStats == nil || replayedStats.Time.IsZero() {
+		t.Fatalf("replayed stats = %+v, want 記録時刻付きの統計", replayedStats)
+	}
+	// 再生した統計には記録時刻が付く以外は記録したときと同じ
+	withoutTime := *replayedStats
+	withoutTime.Time = time.Time{}
+	if !reflect.DeepEqual(&withoutTime, recordedStats) {
+		t.Errorf("replayed stats = %+v, want %+v", replayedStats, recordedStats)
+	}
+}